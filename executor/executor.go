@@ -16,6 +16,7 @@ type Process struct {
 	Stdout io.ReadCloser
 
 	// Stderr is the process standard error.
+	// It is nil when the process was started with ExecuteOptions.CombineStderr.
 	Stderr io.ReadCloser
 
 	// Wait waits for the process to exit and returns any error.
@@ -25,6 +26,15 @@ type Process struct {
 	Kill func() error
 }
 
+// ExecuteOptions customizes how a process is started.
+type ExecuteOptions struct {
+	// CombineStderr sends the process's standard error to the same pipe as
+	// standard output, preserving the relative order of their writes.
+	// Process.Stderr is nil when set, and the two streams can no longer be
+	// told apart.
+	CombineStderr bool
+}
+
 // Executor builds and executes processes.
 // Different implementations can provide different build/execution strategies
 // (e.g., Nix flakes, direct binary execution, containers, etc.).
@@ -36,3 +46,11 @@ type Executor interface {
 	// Execute starts a process with the given executable path and arguments.
 	Execute(ctx context.Context, path string, args []string) (*Process, error)
 }
+
+// OptionsExecutor is implemented by executors that accept ExecuteOptions.
+type OptionsExecutor interface {
+	Executor
+
+	// ExecuteWithOptions starts a process like Execute, customized by opts.
+	ExecuteWithOptions(ctx context.Context, path string, args []string, opts ExecuteOptions) (*Process, error)
+}
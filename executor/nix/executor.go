@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -114,6 +115,12 @@ func findExecutable(storePath string) (string, error) {
 
 // Execute starts a process with the given executable path and arguments.
 func (e *Executor) Execute(ctx context.Context, path string, args []string) (*executor.Process, error) {
+	return e.ExecuteWithOptions(ctx, path, args, executor.ExecuteOptions{})
+}
+
+// ExecuteWithOptions starts a process with the given executable path and
+// arguments, customized by opts.
+func (e *Executor) ExecuteWithOptions(ctx context.Context, path string, args []string, opts executor.ExecuteOptions) (*executor.Process, error) {
 	cmd := exec.CommandContext(ctx, path, args...)
 
 	// Set up pipes for stdin, stdout, stderr
@@ -128,18 +135,26 @@ func (e *Executor) Execute(ctx context.Context, path string, args []string) (*ex
 		return nil, fmt.Errorf("creating stdout pipe: %w", err)
 	}
 
-	stderr, err := cmd.StderrPipe()
-	if err != nil {
-		stdin.Close()
-		stdout.Close()
-		return nil, fmt.Errorf("creating stderr pipe: %w", err)
+	var stderr io.ReadCloser
+	if opts.CombineStderr {
+		// StdoutPipe set cmd.Stdout to the pipe's write end; share it.
+		cmd.Stderr = cmd.Stdout
+	} else {
+		stderr, err = cmd.StderrPipe()
+		if err != nil {
+			stdin.Close()
+			stdout.Close()
+			return nil, fmt.Errorf("creating stderr pipe: %w", err)
+		}
 	}
 
 	// Start the process
 	if err := cmd.Start(); err != nil {
 		stdin.Close()
 		stdout.Close()
-		stderr.Close()
+		if stderr != nil {
+			stderr.Close()
+		}
 		return nil, fmt.Errorf("starting process: %w", err)
 	}
 
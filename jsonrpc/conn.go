@@ -77,7 +77,7 @@ func (c *Conn) handleMessage(ctx context.Context, msg *Message) {
 	resp, err := c.handler(ctx, msg)
 	if err != nil {
 		if msg.IsRequest() {
-			errResp, _ := NewErrorResponse(*msg.ID, InternalError, err.Error(), nil)
+			errResp, _ := NewErrorResponse(msg.RequestID(), InternalError, err.Error(), nil)
 			c.stream.Write(errResp)
 		}
 		return
@@ -0,0 +1,30 @@
+package jsonrpc
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// IDGenerator produces monotonic, collision-free request IDs for requests
+// originated locally (e.g. server-to-client requests over a connection where
+// the peer also sends requests).
+//
+// IDs are strings of the form "<prefix>-<n>". Pending requests are usually
+// correlated by ID.String(), under which the number 1 and the string "1" are
+// indistinguishable; a prefix keeps locally originated IDs from ever matching
+// the numeric or unprefixed IDs a peer picks for its own requests, and makes
+// the origin of each ID obvious in logs.
+type IDGenerator struct {
+	prefix string
+	next   atomic.Int64
+}
+
+// NewIDGenerator creates a generator whose IDs start with prefix.
+func NewIDGenerator(prefix string) *IDGenerator {
+	return &IDGenerator{prefix: prefix}
+}
+
+// Next returns the next ID. It is safe for concurrent use.
+func (g *IDGenerator) Next() ID {
+	return NewStringID(fmt.Sprintf("%s-%d", g.prefix, g.next.Add(1)))
+}
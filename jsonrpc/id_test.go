@@ -0,0 +1,52 @@
+package jsonrpc
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestIDGeneratorPrefix(t *testing.T) {
+	g := NewIDGenerator("srv")
+
+	if got := g.Next().String(); got != "srv-1" {
+		t.Fatalf("first ID = %q, want %q", got, "srv-1")
+	}
+	if got := g.Next().String(); got != "srv-2" {
+		t.Fatalf("second ID = %q, want %q", got, "srv-2")
+	}
+}
+
+func TestIDGeneratorConcurrent(t *testing.T) {
+	g := NewIDGenerator("srv")
+
+	const goroutines, perGoroutine = 16, 500
+
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ids := make([]string, perGoroutine)
+			for j := range ids {
+				ids[j] = g.Next().String()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			for _, id := range ids {
+				if seen[id] {
+					t.Errorf("duplicate ID %q", id)
+				}
+				seen[id] = true
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(seen) != goroutines*perGoroutine {
+		t.Fatalf("unique IDs = %d, want %d", len(seen), goroutines*perGoroutine)
+	}
+}
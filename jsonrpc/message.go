@@ -97,18 +97,34 @@ const (
 	ContentModified      = -32801
 )
 
+// IsRequest reports whether m is a method call carrying a non-null ID.
 func (m *Message) IsRequest() bool {
-	return m.ID != nil && m.Method != ""
+	return m.hasID() && m.Method != ""
 }
 
+// IsNotification reports whether m is a method call without an ID.
+// A null ID is treated the same as a missing one.
 func (m *Message) IsNotification() bool {
-	return m.ID == nil && m.Method != ""
+	return !m.hasID() && m.Method != ""
 }
 
 func (m *Message) IsResponse() bool {
 	return m.ID != nil && m.Method == ""
 }
 
+// RequestID returns the message's ID, or a null ID if it has none.
+// Unlike dereferencing ID it never panics.
+func (m *Message) RequestID() ID {
+	if m.ID == nil {
+		return ID{}
+	}
+	return *m.ID
+}
+
+func (m *Message) hasID() bool {
+	return m.ID != nil && !m.ID.IsNull()
+}
+
 func NewRequest(id ID, method string, params any) (*Message, error) {
 	var rawParams json.RawMessage
 	if params != nil {
@@ -1,5 +1,10 @@
 package output
 
+import (
+	"encoding/json"
+	"fmt"
+)
+
 // ArrayLimits controls pagination of array results.
 // Zero values mean unlimited (Limit) or no offset (Offset).
 type ArrayLimits struct {
@@ -23,6 +28,12 @@ type LimitedArray[T any] struct {
 	Pagination PaginationInfo `json:"pagination"`
 }
 
+// DroppedItems returns how many items were left out of Items, counting both
+// those skipped by the offset and those beyond the limit.
+func (la LimitedArray[T]) DroppedItems() int {
+	return la.TotalCount - len(la.Items)
+}
+
 // LimitArray applies pagination limits to a slice.
 // The returned Items is a subslice (no copy). Offset is clamped to the slice length.
 func LimitArray[T any](items []T, limits ArrayLimits) LimitedArray[T] {
@@ -66,3 +77,61 @@ func LimitArray[T any](items []T, limits ArrayLimits) LimitedArray[T] {
 		},
 	}
 }
+
+// ByteLimitedArray is the result of LimitArrayBytes.
+type ByteLimitedArray[T any] struct {
+	Items      []T  `json:"items"`
+	Truncated  bool `json:"truncated"`
+	TotalCount int  `json:"total_count"`
+
+	// Bytes is the size of Items marshaled as a JSON array.
+	Bytes int `json:"bytes"`
+
+	// Oversize is set when the first item alone exceeds the budget.
+	// It is included anyway so the result is never empty for a non-empty input.
+	Oversize bool `json:"oversize,omitempty"`
+}
+
+// LimitArrayBytes keeps the leading items whose JSON array encoding fits in
+// maxBytes, marshaling items one at a time and stopping before the first
+// item that would exceed the budget. The first item is always kept, with
+// Oversize set if it does not fit. A maxBytes of zero or less means
+// unlimited. The returned Items is a subslice (no copy).
+func LimitArrayBytes[T any](items []T, maxBytes int) (ByteLimitedArray[T], error) {
+	total := len(items)
+
+	// Account for the enclosing brackets.
+	size := 2
+	kept := 0
+	oversize := false
+
+	for i, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return ByteLimitedArray[T]{}, fmt.Errorf("marshaling item %d: %w", i, err)
+		}
+
+		next := size + len(data)
+		if i > 0 {
+			next++ // separating comma
+		}
+
+		if maxBytes > 0 && next > maxBytes {
+			if i > 0 {
+				break
+			}
+			oversize = true
+		}
+
+		size = next
+		kept++
+	}
+
+	return ByteLimitedArray[T]{
+		Items:      items[:kept],
+		Truncated:  kept != total,
+		TotalCount: total,
+		Bytes:      size,
+		Oversize:   oversize,
+	}, nil
+}
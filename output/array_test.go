@@ -189,3 +189,80 @@ func TestLimitArrayPaginationInfo(t *testing.T) {
 		t.Fatal("expected HasMore=true (items 8,9,10 remain)")
 	}
 }
+
+func TestLimitArrayDroppedItems(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5, 6}
+	result := LimitArray(items, ArrayLimits{Offset: 1, Limit: 2})
+
+	if got := result.DroppedItems(); got != 4 {
+		t.Fatalf("expected 4 dropped items, got %d", got)
+	}
+
+	if got := LimitArray(items, ArrayLimits{}).DroppedItems(); got != 0 {
+		t.Fatalf("expected 0 dropped items without limits, got %d", got)
+	}
+}
+
+func TestLimitArrayBytesStopsAtBudget(t *testing.T) {
+	items := []string{"aaaa", "bbbb", "cccc"}
+
+	// ["aaaa","bbbb"] is 15 bytes; adding ,"cccc" would make 22.
+	result, err := LimitArrayBytes(items, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(result.Items))
+	}
+
+	if !result.Truncated {
+		t.Fatal("expected truncation")
+	}
+
+	if result.Bytes != 15 {
+		t.Fatalf("expected 15 bytes, got %d", result.Bytes)
+	}
+
+	if result.Oversize {
+		t.Fatal("expected no oversize flag")
+	}
+}
+
+func TestLimitArrayBytesOversizeFirstItem(t *testing.T) {
+	items := []string{"a very long first item", "b"}
+
+	result, err := LimitArrayBytes(items, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Items) != 1 {
+		t.Fatalf("expected the first item to be kept, got %d items", len(result.Items))
+	}
+
+	if !result.Oversize {
+		t.Fatal("expected oversize flag")
+	}
+
+	if !result.Truncated {
+		t.Fatal("expected truncation")
+	}
+}
+
+func TestLimitArrayBytesUnlimited(t *testing.T) {
+	items := []int{1, 2, 3}
+
+	result, err := LimitArrayBytes(items, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Truncated || len(result.Items) != 3 {
+		t.Fatalf("expected all items, got %d", len(result.Items))
+	}
+
+	if result.Bytes != len("[1,2,3]") {
+		t.Fatalf("expected %d bytes, got %d", len("[1,2,3]"), result.Bytes)
+	}
+}
@@ -0,0 +1,20 @@
+package output
+
+import "context"
+
+type defaultsKey struct{}
+
+// WithDefaults returns a copy of ctx carrying d, for DefaultsFromContext.
+func WithDefaults(ctx context.Context, d Defaults) context.Context {
+	return context.WithValue(ctx, defaultsKey{}, d)
+}
+
+// DefaultsFromContext returns the Defaults attached to ctx with WithDefaults,
+// such as the per-client defaults a server derives at initialize, or
+// StandardDefaults if there are none.
+func DefaultsFromContext(ctx context.Context) Defaults {
+	if d, ok := ctx.Value(defaultsKey{}).(Defaults); ok {
+		return d
+	}
+	return StandardDefaults()
+}
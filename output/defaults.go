@@ -39,3 +39,11 @@ func (d Defaults) MergeArrayLimits(user ArrayLimits) ArrayLimits {
 
 	return user
 }
+
+// ApplyTextLimits merges user with d, applies the result to input, and
+// returns it along with the effective limits, so callers can report which
+// limits truncated the output.
+func ApplyTextLimits(input string, user TextLimits, d Defaults) (LimitedText, TextLimits) {
+	limits := d.MergeTextLimits(user)
+	return LimitText(input, limits), limits
+}
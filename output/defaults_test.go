@@ -1,6 +1,9 @@
 package output
 
-import "testing"
+import (
+	"context"
+	"testing"
+)
 
 func TestStandardDefaults(t *testing.T) {
 	d := StandardDefaults()
@@ -99,3 +102,27 @@ func TestMergeArrayLimitsOffsetPreserved(t *testing.T) {
 		t.Fatalf("expected Limit filled from default, got %d", merged.Limit)
 	}
 }
+
+func TestDefaultsFromContext(t *testing.T) {
+	if d := DefaultsFromContext(context.Background()); d != StandardDefaults() {
+		t.Fatalf("expected standard defaults without a value, got %+v", d)
+	}
+
+	custom := Defaults{MaxBytes: 10, MaxLines: 2, MaxItems: 3}
+	if d := DefaultsFromContext(WithDefaults(context.Background(), custom)); d != custom {
+		t.Fatalf("expected %+v, got %+v", custom, d)
+	}
+}
+
+func TestApplyTextLimitsReturnsEffectiveLimits(t *testing.T) {
+	d := Defaults{MaxBytes: 1000, MaxLines: 2}
+
+	result, limits := ApplyTextLimits("a\nb\nc\n", TextLimits{Head: 5}, d)
+
+	if limits.MaxLines != 2 || limits.MaxBytes != 1000 || limits.Head != 5 {
+		t.Fatalf("unexpected effective limits %+v", limits)
+	}
+	if !result.Truncated || result.Content != "a\nb" {
+		t.Fatalf("expected truncation to two lines, got %+v", result)
+	}
+}
@@ -0,0 +1,42 @@
+package output
+
+// LineSlice is the result of SliceLines.
+type LineSlice struct {
+	// Content holds the selected lines, each with its original newline.
+	Content string `json:"content"`
+
+	// Start and End are the 1-based, inclusive line numbers actually
+	// selected after clamping. Both are zero if no lines were selected.
+	Start int `json:"start"`
+	End   int `json:"end"`
+
+	// TotalLines is the number of lines in the input.
+	TotalLines int `json:"total_lines"`
+}
+
+// SliceLines selects lines start through end (1-based, inclusive) from input,
+// clamping the range to the lines that exist. An end of zero or less means
+// the last line. Lines are counted as in LimitText.
+func SliceLines(input string, start, end int) LineSlice {
+	lines := splitLines(input)
+	total := len(lines)
+
+	if start < 1 {
+		start = 1
+	}
+	if end <= 0 || end > total {
+		end = total
+	}
+	if start > end {
+		return LineSlice{TotalLines: total}
+	}
+
+	trailingNewline := end < total || (len(input) > 0 && input[len(input)-1] == '\n')
+
+	return LineSlice{
+		Content:    joinLines(lines[start-1:end], trailingNewline),
+		Start:      start,
+		End:        end,
+		TotalLines: total,
+	}
+}
@@ -8,20 +8,34 @@ import (
 // TextLimits controls how text output is truncated.
 // Head and Tail are mutually exclusive; Head takes priority when both are set.
 // Zero values mean unlimited.
+//
+// By default, line truncation drops the input's trailing newline.
+// PreserveTrailingNewline keeps a single trailing newline after Head, Tail, or
+// MaxLines truncation when the input had one; it counts toward KeptBytes.
+//
+// MaxUTF16Units limits length in UTF-16 code units, the way JavaScript
+// clients measure strings; a character outside the Basic Multilingual Plane,
+// such as most emoji, counts as two units and is never split.
 type TextLimits struct {
-	Head     int `json:"head,omitempty"`
-	Tail     int `json:"tail,omitempty"`
-	MaxLines int `json:"max_lines,omitempty"`
-	MaxBytes int `json:"max_bytes,omitempty"`
+	Head          int `json:"head,omitempty"`
+	Tail          int `json:"tail,omitempty"`
+	MaxLines      int `json:"max_lines,omitempty"`
+	MaxBytes      int `json:"max_bytes,omitempty"`
+	MaxUTF16Units int `json:"max_utf16_units,omitempty"`
+
+	PreserveTrailingNewline bool `json:"preserve_trailing_newline,omitempty"`
 }
 
 // TruncationInfo describes what was removed during truncation.
+// The UTF-16 counts are only reported when TextLimits.MaxUTF16Units is set.
 type TruncationInfo struct {
-	OriginalBytes int    `json:"original_bytes"`
-	OriginalLines int    `json:"original_lines"`
-	KeptBytes     int    `json:"kept_bytes"`
-	KeptLines     int    `json:"kept_lines"`
-	Position      string `json:"position"`
+	OriginalBytes      int    `json:"original_bytes"`
+	OriginalLines      int    `json:"original_lines"`
+	OriginalUTF16Units int    `json:"original_utf16_units,omitempty"`
+	KeptBytes          int    `json:"kept_bytes"`
+	KeptLines          int    `json:"kept_lines"`
+	KeptUTF16Units     int    `json:"kept_utf16_units,omitempty"`
+	Position           string `json:"position"`
 }
 
 // LimitedText is the result of applying TextLimits to a string.
@@ -31,10 +45,28 @@ type LimitedText struct {
 	TruncationInfo *TruncationInfo `json:"truncation_info,omitempty"`
 }
 
+// DroppedBytes returns how many bytes truncation removed, or zero if the
+// text was not truncated.
+func (lt LimitedText) DroppedBytes() int {
+	if lt.TruncationInfo == nil {
+		return 0
+	}
+	return lt.TruncationInfo.OriginalBytes - lt.TruncationInfo.KeptBytes
+}
+
+// DroppedLines returns how many lines truncation removed, or zero if the
+// text was not truncated.
+func (lt LimitedText) DroppedLines() int {
+	if lt.TruncationInfo == nil {
+		return 0
+	}
+	return lt.TruncationInfo.OriginalLines - lt.TruncationInfo.KeptLines
+}
+
 // LimitText applies the given limits to the input string.
-// Processing order: Head/Tail, then MaxLines, then MaxBytes.
+// Processing order: Head/Tail, then MaxLines, then MaxBytes, then MaxUTF16Units.
 func LimitText(input string, limits TextLimits) LimitedText {
-	if input == "" {
+	if input == "" || withinLimits(input, limits) {
 		return LimitedText{Content: input}
 	}
 
@@ -69,7 +101,8 @@ func LimitText(input string, limits TextLimits) LimitedText {
 	}
 
 	// Rejoin before byte limiting
-	content := joinLines(result, trailingNewline && position == "")
+	keepNewline := trailingNewline && (position == "" || limits.PreserveTrailingNewline)
+	content := joinLines(result, keepNewline)
 
 	// Step 3: MaxBytes
 	if limits.MaxBytes > 0 && len(content) > limits.MaxBytes {
@@ -81,6 +114,15 @@ func LimitText(input string, limits TextLimits) LimitedText {
 		result = splitLines(content)
 	}
 
+	// Step 4: MaxUTF16Units
+	if limits.MaxUTF16Units > 0 && utf16Len(content) > limits.MaxUTF16Units {
+		content = truncateAtBoundary(content, utf16Offset(content, limits.MaxUTF16Units))
+		if position == "" {
+			position = "head"
+		}
+		result = splitLines(content)
+	}
+
 	truncated := len(content) != originalBytes
 	if !truncated {
 		return LimitedText{Content: content}
@@ -88,19 +130,51 @@ func LimitText(input string, limits TextLimits) LimitedText {
 
 	keptLines := len(result)
 
+	info := &TruncationInfo{
+		OriginalBytes: originalBytes,
+		OriginalLines: originalLines,
+		KeptBytes:     len(content),
+		KeptLines:     keptLines,
+		Position:      position,
+	}
+	if limits.MaxUTF16Units > 0 {
+		info.OriginalUTF16Units = utf16Len(input)
+		info.KeptUTF16Units = utf16Len(content)
+	}
+
 	return LimitedText{
-		Content:   content,
-		Truncated: true,
-		TruncationInfo: &TruncationInfo{
-			OriginalBytes: originalBytes,
-			OriginalLines: originalLines,
-			KeptBytes:     len(content),
-			KeptLines:     keptLines,
-			Position:      position,
-		},
+		Content:        content,
+		Truncated:      true,
+		TruncationInfo: info,
 	}
 }
 
+// withinLimits reports, without splitting, whether LimitText would return
+// input unchanged. Inputs ending in several newlines are left to the slow path,
+// which collapses them.
+func withinLimits(input string, limits TextLimits) bool {
+	if strings.HasSuffix(input, "\n\n") {
+		return false
+	}
+	if limits.MaxBytes > 0 && len(input) > limits.MaxBytes {
+		return false
+	}
+	// UTF-16 never needs more units than UTF-8 needs bytes, so only count
+	// when the byte length alone does not settle it.
+	if limits.MaxUTF16Units > 0 && len(input) > limits.MaxUTF16Units &&
+		utf16Len(input) > limits.MaxUTF16Units {
+		return false
+	}
+	if limits.Head <= 0 && limits.Tail <= 0 && limits.MaxLines <= 0 {
+		return true
+	}
+
+	lines := strings.Count(strings.TrimSuffix(input, "\n"), "\n") + 1
+	return (limits.Head <= 0 || limits.Head >= lines) &&
+		(limits.Tail <= 0 || limits.Tail >= lines) &&
+		(limits.MaxLines <= 0 || limits.MaxLines >= lines)
+}
+
 // splitLines splits input into lines without producing phantom empty entries
 // from trailing newlines.
 func splitLines(s string) []string {
@@ -147,6 +221,37 @@ func truncateAtBoundary(s string, maxBytes int) string {
 	return truncateUTF8(truncated, maxBytes)
 }
 
+// utf16Len returns the length of s in UTF-16 code units. Invalid UTF-8 bytes
+// count as one unit each, as they decode to U+FFFD.
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// utf16Offset returns the byte offset in s of the longest prefix that fits in
+// maxUnits UTF-16 code units, without splitting a surrogate pair.
+func utf16Offset(s string, maxUnits int) int {
+	n := 0
+	for i, r := range s {
+		units := 1
+		if r >= 0x10000 {
+			units = 2
+		}
+		if n+units > maxUnits {
+			return i
+		}
+		n += units
+	}
+	return len(s)
+}
+
 // truncateUTF8 ensures we don't cut in the middle of a multi-byte rune.
 func truncateUTF8(s string, maxBytes int) string {
 	if maxBytes < len(s) {
@@ -314,3 +314,142 @@ func TestLimitTextLargeInput(t *testing.T) {
 		t.Fatalf("expected content <= 1000 bytes, got %d", len(result.Content))
 	}
 }
+
+func TestLimitTextDroppedCounts(t *testing.T) {
+	input := "line1\nline2\nline3\nline4\nline5\n"
+	result := LimitText(input, TextLimits{Head: 2})
+
+	if got := result.DroppedLines(); got != 3 {
+		t.Fatalf("expected 3 dropped lines, got %d", got)
+	}
+
+	if got := result.DroppedBytes(); got != len(input)-len(result.Content) {
+		t.Fatalf("expected %d dropped bytes, got %d", len(input)-len(result.Content), got)
+	}
+}
+
+func TestLimitTextDroppedCountsNotTruncated(t *testing.T) {
+	result := LimitText("hello\n", TextLimits{Head: 5})
+
+	if result.DroppedBytes() != 0 || result.DroppedLines() != 0 {
+		t.Fatal("expected zero dropped counts when not truncated")
+	}
+}
+
+func TestLimitTextHeadPreserveTrailingNewline(t *testing.T) {
+	input := "line1\nline2\nline3\n"
+	result := LimitText(input, TextLimits{Head: 2, PreserveTrailingNewline: true})
+
+	if result.Content != "line1\nline2\n" {
+		t.Fatalf("expected trailing newline preserved, got %q", result.Content)
+	}
+
+	if result.TruncationInfo.KeptBytes != len("line1\nline2\n") {
+		t.Fatalf("expected kept bytes to include newline, got %d", result.TruncationInfo.KeptBytes)
+	}
+
+	if result.TruncationInfo.KeptLines != 2 {
+		t.Fatalf("expected 2 kept lines, got %d", result.TruncationInfo.KeptLines)
+	}
+}
+
+func TestLimitTextPreserveTrailingNewlineWithoutOriginal(t *testing.T) {
+	result := LimitText("a\nb\nc", TextLimits{MaxLines: 2, PreserveTrailingNewline: true})
+
+	if result.Content != "a\nb" {
+		t.Fatalf("expected no newline added when input lacked one, got %q", result.Content)
+	}
+}
+
+var benchSmallOutput = strings.Repeat("a line of tool output\n", 20)
+
+func BenchmarkLimitTextNoLimits(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		LimitText(benchSmallOutput, TextLimits{})
+	}
+}
+
+func BenchmarkLimitTextWithinLimits(b *testing.B) {
+	limits := StandardDefaults().MergeTextLimits(TextLimits{})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		LimitText(benchSmallOutput, limits)
+	}
+}
+
+func BenchmarkLimitTextTruncated(b *testing.B) {
+	limits := TextLimits{MaxLines: 5}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		LimitText(benchSmallOutput, limits)
+	}
+}
+
+func TestSliceLines(t *testing.T) {
+	input := "one\ntwo\nthree\nfour\n"
+
+	got := SliceLines(input, 2, 3)
+	if got.Content != "two\nthree\n" {
+		t.Fatalf("expected %q, got %q", "two\nthree\n", got.Content)
+	}
+
+	if got.Start != 2 || got.End != 3 || got.TotalLines != 4 {
+		t.Fatalf("unexpected range %d-%d of %d", got.Start, got.End, got.TotalLines)
+	}
+}
+
+func TestSliceLinesClamps(t *testing.T) {
+	got := SliceLines("one\ntwo", 0, 100)
+	if got.Content != "one\ntwo" {
+		t.Fatalf("expected whole input, got %q", got.Content)
+	}
+
+	if got.Start != 1 || got.End != 2 {
+		t.Fatalf("expected clamped range 1-2, got %d-%d", got.Start, got.End)
+	}
+
+	past := SliceLines("one\ntwo", 5, 10)
+	if past.Content != "" || past.TotalLines != 2 {
+		t.Fatalf("expected empty slice past the end, got %+v", past)
+	}
+}
+
+func TestLimitTextMaxUTF16Units(t *testing.T) {
+	// "😀" is one rune, four UTF-8 bytes and two UTF-16 units (a surrogate pair).
+	input := "ab😀cd"
+	result := LimitText(input, TextLimits{MaxUTF16Units: 3})
+
+	if !result.Truncated {
+		t.Fatal("expected truncation")
+	}
+	if result.Content != "ab" {
+		t.Fatalf("expected surrogate-safe truncation to %q, got %q", "ab", result.Content)
+	}
+
+	info := result.TruncationInfo
+	if info.OriginalUTF16Units != 6 || info.KeptUTF16Units != 2 {
+		t.Fatalf("UTF-16 units = %d/%d, want 6/2", info.KeptUTF16Units, info.OriginalUTF16Units)
+	}
+	if info.OriginalBytes != 8 || info.KeptBytes != 2 {
+		t.Fatalf("bytes = %d/%d, want 8/2", info.KeptBytes, info.OriginalBytes)
+	}
+}
+
+func TestLimitTextMaxUTF16UnitsCountsCJKAsOne(t *testing.T) {
+	// Each CJK rune is three UTF-8 bytes but a single UTF-16 unit.
+	input := "日本語"
+	result := LimitText(input, TextLimits{MaxUTF16Units: 3})
+
+	if result.Truncated {
+		t.Fatalf("expected no truncation, got %q", result.Content)
+	}
+
+	result = LimitText(input, TextLimits{MaxUTF16Units: 2})
+	if result.Content != "日本" {
+		t.Fatalf("expected %q, got %q", "日本", result.Content)
+	}
+	if result.TruncationInfo.KeptUTF16Units != 2 {
+		t.Fatalf("KeptUTF16Units = %d, want 2", result.TruncationInfo.KeptUTF16Units)
+	}
+}
@@ -0,0 +1,25 @@
+package output
+
+// TextTruncator is a strategy for applying TextLimits to text output.
+// Implementations may interpret the limits to suit a content type, e.g.
+// keeping JSON valid or cutting only between CSV rows.
+type TextTruncator interface {
+	Truncate(input string, limits TextLimits) LimitedText
+}
+
+// TextTruncatorFunc adapts a function to the TextTruncator interface.
+type TextTruncatorFunc func(input string, limits TextLimits) LimitedText
+
+// Truncate implements TextTruncator.
+func (f TextTruncatorFunc) Truncate(input string, limits TextLimits) LimitedText {
+	return f(input, limits)
+}
+
+// LineTruncator is the default TextTruncator. It cuts by lines, then bytes,
+// exactly like LimitText.
+type LineTruncator struct{}
+
+// Truncate implements TextTruncator.
+func (LineTruncator) Truncate(input string, limits TextLimits) LimitedText {
+	return LimitText(input, limits)
+}
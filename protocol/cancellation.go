@@ -0,0 +1,12 @@
+package protocol
+
+import "encoding/json"
+
+// CancelledNotification is the params of a notifications/cancelled notification.
+type CancelledNotification struct {
+	// RequestID is the ID of the request to cancel.
+	RequestID json.RawMessage `json:"requestId"`
+
+	// Reason optionally explains why the request was cancelled.
+	Reason string `json:"reason,omitempty"`
+}
@@ -0,0 +1,167 @@
+package protocol
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// ImageContent creates a ContentBlock containing base64-encoded image data.
+func ImageContent(data []byte, mimeType string) ContentBlock {
+	return ContentBlock{
+		Type:     "image",
+		MimeType: mimeType,
+		Data:     base64.StdEncoding.EncodeToString(data),
+	}
+}
+
+// ImageContentFromImage encodes img as "png" or "jpeg" and returns it as an
+// image ContentBlock with the matching MIME type.
+func ImageContentFromImage(img image.Image, format string) (ContentBlock, error) {
+	var buf bytes.Buffer
+	var mimeType string
+
+	switch strings.ToLower(format) {
+	case "png":
+		mimeType = "image/png"
+		if err := png.Encode(&buf, img); err != nil {
+			return ContentBlock{}, fmt.Errorf("encoding png: %w", err)
+		}
+	case "jpeg", "jpg":
+		mimeType = "image/jpeg"
+		if err := jpeg.Encode(&buf, img, nil); err != nil {
+			return ContentBlock{}, fmt.Errorf("encoding jpeg: %w", err)
+		}
+	default:
+		return ContentBlock{}, fmt.Errorf("unsupported image format: %q", format)
+	}
+
+	return ImageContent(buf.Bytes(), mimeType), nil
+}
+
+// ResourceBlock creates a ContentBlock embedding a resource. The content may
+// carry only a URI and MIME type, leaving the client to read it on demand.
+func ResourceBlock(content ResourceContent) ContentBlock {
+	return ContentBlock{Type: "resource", Resource: &content}
+}
+
+// bytesContent picks the block type for raw data by sniffing its MIME type.
+func bytesContent(data []byte) ContentBlock {
+	mimeType := http.DetectContentType(data)
+	if strings.HasPrefix(mimeType, "image/") {
+		return ImageContent(data, mimeType)
+	}
+	if utf8.Valid(data) {
+		return TextContent(string(data))
+	}
+
+	sum := sha256.Sum256(data)
+	return ResourceBlock(ResourceContent{
+		URI:      "urn:sha256:" + hex.EncodeToString(sum[:]),
+		MimeType: mimeType,
+		Blob:     base64.StdEncoding.EncodeToString(data),
+	})
+}
+
+// MaxFileContentSize is the largest file FileContent will read, in bytes.
+const MaxFileContentSize = 10 << 20
+
+// FileContent reads the file at path and returns it as an image block if its
+// MIME type is an image type, or as an embedded resource blob with a file://
+// URI otherwise. The MIME type is detected from the extension, falling back
+// to sniffing the data. Files larger than MaxFileContentSize are rejected.
+func FileContent(path string) (ContentBlock, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return ContentBlock{}, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return ContentBlock{}, err
+	}
+	if info.IsDir() {
+		return ContentBlock{}, fmt.Errorf("%s is a directory", path)
+	}
+	if info.Size() > MaxFileContentSize {
+		return ContentBlock{}, fmt.Errorf("file %s is %d bytes, larger than the %d byte limit",
+			path, info.Size(), MaxFileContentSize)
+	}
+
+	// Bound the read as well, in case the file grows or its size is not
+	// reported, e.g. for a pipe.
+	data, err := io.ReadAll(io.LimitReader(f, MaxFileContentSize+1))
+	if err != nil {
+		return ContentBlock{}, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if len(data) > MaxFileContentSize {
+		return ContentBlock{}, fmt.Errorf("file %s is larger than the %d byte limit", path, MaxFileContentSize)
+	}
+
+	mimeType := mime.TypeByExtension(filepath.Ext(path))
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+	if strings.HasPrefix(mimeType, "image/") {
+		return ImageContent(data, mimeType), nil
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return ContentBlock{}, err
+	}
+	return ResourceBlock(ResourceContent{
+		URI:      (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}).String(),
+		MimeType: mimeType,
+		Blob:     base64.StdEncoding.EncodeToString(data),
+	}), nil
+}
+
+// AutoContent creates a ContentBlock from an arbitrary value.
+//
+// Detection rules, in order:
+//   - ContentBlock values are returned unchanged.
+//   - nil produces an empty text block.
+//   - string, error, and fmt.Stringer values produce a text block.
+//   - []byte is sniffed with http.DetectContentType: image data produces an
+//     image block, valid UTF-8 a text block, and anything else an embedded
+//     resource blob identified by the data's SHA-256 digest.
+//   - Anything else (structs, maps, slices, numbers) is marshaled as indented
+//     JSON into a text block, falling back to fmt.Sprint if marshaling fails.
+func AutoContent(v any) ContentBlock {
+	switch v := v.(type) {
+	case ContentBlock:
+		return v
+	case nil:
+		return TextContent("")
+	case string:
+		return TextContent(v)
+	case error:
+		return TextContent(v.Error())
+	case fmt.Stringer:
+		return TextContent(v.String())
+	case []byte:
+		return bytesContent(v)
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return TextContent(fmt.Sprint(v))
+	}
+	return TextContent(string(data))
+}
@@ -0,0 +1,36 @@
+package protocol
+
+import "encoding/json"
+
+// ElicitParams are sent by the server to request input from the user.
+type ElicitParams struct {
+	// Message explains to the user what is being asked.
+	Message string `json:"message"`
+
+	// RequestedSchema is a JSON Schema for the response. The spec restricts
+	// it to an object of primitive-typed properties.
+	RequestedSchema json.RawMessage `json:"requestedSchema"`
+}
+
+// ElicitAction is the user's response to an elicitation.
+type ElicitAction string
+
+const (
+	// ElicitActionAccept means the user submitted the requested data.
+	ElicitActionAccept ElicitAction = "accept"
+
+	// ElicitActionDecline means the user explicitly refused.
+	ElicitActionDecline ElicitAction = "decline"
+
+	// ElicitActionCancel means the user dismissed the request without choosing.
+	ElicitActionCancel ElicitAction = "cancel"
+)
+
+// ElicitResult is the client's response to elicitation/create.
+type ElicitResult struct {
+	// Action is what the user did.
+	Action ElicitAction `json:"action"`
+
+	// Content holds the submitted data when Action is accept.
+	Content map[string]any `json:"content,omitempty"`
+}
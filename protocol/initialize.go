@@ -1,10 +1,15 @@
 package protocol
 
+import "encoding/json"
+
 // InitializeParams are sent by the client during initialization.
 type InitializeParams struct {
 	ProtocolVersion string             `json:"protocolVersion"`
 	Capabilities    ClientCapabilities `json:"capabilities"`
 	ClientInfo      Implementation     `json:"clientInfo"`
+
+	// Meta is opaque client metadata, such as non-standard hints (optional).
+	Meta json.RawMessage `json:"_meta,omitempty"`
 }
 
 // InitializeResult is returned by the server in response to initialization.
@@ -14,10 +19,29 @@ type InitializeResult struct {
 	ServerInfo      Implementation     `json:"serverInfo"`
 }
 
+// UnsupportedVersionData is the data of the InvalidParams error returned when
+// a client requests a protocol version older than MinProtocolVersion.
+type UnsupportedVersionData struct {
+	// Requested is the version the client asked for.
+	Requested string `json:"requested"`
+
+	// Minimum is the oldest version the server supports.
+	Minimum string `json:"minimum"`
+
+	// Maximum is the newest version the server supports.
+	Maximum string `json:"maximum"`
+}
+
 // ClientCapabilities describes what the client supports.
 type ClientCapabilities struct {
 	Roots    *RootsCapability    `json:"roots,omitempty"`
 	Sampling *SamplingCapability `json:"sampling,omitempty"`
+	Logging  *LoggingCapability  `json:"logging,omitempty"`
+
+	Elicitation *ElicitationCapability `json:"elicitation,omitempty"`
+
+	// Experimental advertises non-standard extensions the client understands.
+	Experimental map[string]any `json:"experimental,omitempty"`
 }
 
 // RootsCapability indicates client support for workspace roots.
@@ -25,16 +49,62 @@ type RootsCapability struct {
 	ListChanged bool `json:"listChanged,omitempty"`
 }
 
+// Root is a workspace root exposed by the client.
+type Root struct {
+	// URI identifies the root, typically a file:// URI.
+	URI string `json:"uri"`
+
+	// Name is a human-readable name (optional).
+	Name string `json:"name,omitempty"`
+}
+
+// RootsListResult is the client's response to roots/list.
+type RootsListResult struct {
+	Roots []Root `json:"roots"`
+}
+
 // SamplingCapability indicates client support for LLM sampling.
 type SamplingCapability struct{}
 
+// ElicitationCapability indicates client support for elicitation/create.
+type ElicitationCapability struct{}
+
+// LoggingCapability indicates client support for notifications/message.
+type LoggingCapability struct{}
+
 // ServerCapabilities describes what the server supports.
 type ServerCapabilities struct {
 	Tools     *ToolsCapability     `json:"tools,omitempty"`
 	Resources *ResourcesCapability `json:"resources,omitempty"`
 	Prompts   *PromptsCapability   `json:"prompts,omitempty"`
+
+	// Experimental advertises non-standard extensions, keyed by name.
+	Experimental map[string]any `json:"experimental,omitempty"`
 }
 
+// ExperimentalReadMany is the experimental capability key advertising
+// support for the resources/read_many extension.
+const ExperimentalReadMany = "resources/read_many"
+
+// ExperimentalSubscriptionMode is the experimental capability key under which
+// the server advertises which URIs resources/subscribe accepts. Its value is
+// a SubscriptionModeCapability.
+const ExperimentalSubscriptionMode = "resources/subscription_mode"
+
+// SubscriptionModeCapability describes the granularity of resource
+// subscriptions: "exact" (listed resource URIs only), "prefix" (also
+// prefixes of listed URIs, covering every resource under them), or "all"
+// (any URI).
+type SubscriptionModeCapability struct {
+	Mode string `json:"mode"`
+}
+
+// ExperimentalResourceListDiff is the experimental capability key for the
+// notifications/resources/list_diff extension. The server advertises it when
+// it can send diffs; a client advertises it to receive them instead of
+// notifications/resources/list_changed.
+const ExperimentalResourceListDiff = "resources/list_diff"
+
 // ToolsCapability indicates the server supports tools.
 type ToolsCapability struct {
 	ListChanged bool `json:"listChanged,omitempty"`
@@ -0,0 +1,28 @@
+package protocol
+
+// LogLevel is the severity of a log message, following syslog levels.
+type LogLevel string
+
+// Log levels, from least to most severe.
+const (
+	LogLevelDebug     LogLevel = "debug"
+	LogLevelInfo      LogLevel = "info"
+	LogLevelNotice    LogLevel = "notice"
+	LogLevelWarning   LogLevel = "warning"
+	LogLevelError     LogLevel = "error"
+	LogLevelCritical  LogLevel = "critical"
+	LogLevelAlert     LogLevel = "alert"
+	LogLevelEmergency LogLevel = "emergency"
+)
+
+// LoggingMessageNotification is the payload of notifications/message.
+type LoggingMessageNotification struct {
+	// Level is the severity of the message.
+	Level LogLevel `json:"level"`
+
+	// Logger names the component that produced the message (optional).
+	Logger string `json:"logger,omitempty"`
+
+	// Data is the message, a string or any JSON-serializable value.
+	Data any `json:"data"`
+}
@@ -0,0 +1,42 @@
+package protocol
+
+import "encoding/json"
+
+// RequestMeta is the _meta object a client may attach to a request.
+type RequestMeta struct {
+	// ProgressToken asks the server to send notifications/progress for this
+	// request (optional). It is a string or number chosen by the client and
+	// echoed in every notification.
+	ProgressToken json.RawMessage `json:"progressToken,omitempty"`
+}
+
+// ProgressNotification is the params of a notifications/progress notification.
+type ProgressNotification struct {
+	// ProgressToken is the token from the request's _meta.
+	ProgressToken json.RawMessage `json:"progressToken"`
+
+	// Progress is the amount of work done so far. It must increase with
+	// every notification.
+	Progress float64 `json:"progress"`
+
+	// Total is the total amount of work. Zero means unknown (indeterminate
+	// progress) and is omitted.
+	Total float64 `json:"total,omitempty"`
+
+	// Message is a human-readable description of the current step (optional).
+	Message string `json:"message,omitempty"`
+
+	// Meta carries extension data (optional).
+	Meta *ProgressMeta `json:"_meta,omitempty"`
+}
+
+// ProgressMeta carries extension data on progress notifications.
+// Clients that do not understand it can ignore it.
+type ProgressMeta struct {
+	// PartialContent is incremental tool output sent before the final result.
+	PartialContent []ContentBlock `json:"partialContent,omitempty"`
+
+	// StructuredContent is one incremental machine-readable result, such as
+	// an object from a tool's NDJSON output.
+	StructuredContent json.RawMessage `json:"structuredContent,omitempty"`
+}
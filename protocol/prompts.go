@@ -22,6 +22,10 @@ type PromptArgument struct {
 
 	// Required indicates whether this parameter must be provided.
 	Required bool `json:"required,omitempty"`
+
+	// Enum restricts the parameter to a fixed set of values, letting clients
+	// offer a choice (optional).
+	Enum []string `json:"enum,omitempty"`
 }
 
 // PromptsListResult is the response to prompts/list.
@@ -2,9 +2,15 @@
 // MCP is a protocol for communication between AI assistants and context providers.
 package protocol
 
+import "encoding/json"
+
 // ProtocolVersion is the MCP protocol version this library implements.
 const ProtocolVersion = "2024-11-05"
 
+// MinProtocolVersion is the oldest protocol version a client may request.
+// Protocol versions are dates, so they order lexically.
+const MinProtocolVersion = "2024-11-05"
+
 // MCP method name constants define the available protocol methods.
 const (
 	// MethodInitialize is sent by the client to initialize the connection.
@@ -28,14 +34,61 @@ const (
 	// MethodResourcesRead reads the content of a resource.
 	MethodResourcesRead = "resources/read"
 
+	// MethodResourcesReadMany reads several resources in one call.
+	// This is an experimental extension advertised under ExperimentalReadMany.
+	MethodResourcesReadMany = "resources/read_many"
+
 	// MethodResourcesTemplates lists resource URI templates.
 	MethodResourcesTemplates = "resources/templates/list"
 
+	// MethodResourcesSubscribe asks to be notified when a resource changes.
+	MethodResourcesSubscribe = "resources/subscribe"
+
+	// MethodResourcesUnsubscribe cancels a resources/subscribe.
+	MethodResourcesUnsubscribe = "resources/unsubscribe"
+
+	// MethodResourcesUpdated notifies the client that a subscribed resource changed.
+	MethodResourcesUpdated = "notifications/resources/updated"
+
 	// MethodPromptsList requests the list of available prompts.
 	MethodPromptsList = "prompts/list"
 
 	// MethodPromptsGet retrieves a prompt with arguments.
 	MethodPromptsGet = "prompts/get"
+
+	// MethodRootsList asks the client for its workspace roots.
+	MethodRootsList = "roots/list"
+
+	// MethodRootsListChanged notifies the server that the client's roots changed.
+	MethodRootsListChanged = "notifications/roots/list_changed"
+
+	// MethodProgress reports progress on a long-running request.
+	MethodProgress = "notifications/progress"
+
+	// MethodCancelled asks the receiver to stop working on an earlier request.
+	MethodCancelled = "notifications/cancelled"
+
+	// MethodToolsListChanged notifies the client that the tool list changed.
+	MethodToolsListChanged = "notifications/tools/list_changed"
+
+	// MethodResourcesListChanged notifies the client that the resource list changed.
+	MethodResourcesListChanged = "notifications/resources/list_changed"
+
+	// MethodPromptsListChanged notifies the client that the prompt list changed.
+	MethodPromptsListChanged = "notifications/prompts/list_changed"
+
+	// MethodResourcesListDiff is an experimental notification carrying the
+	// resources added and removed since the previous one.
+	MethodResourcesListDiff = "notifications/resources/list_diff"
+
+	// MethodSamplingCreateMessage asks the client to sample from its LLM.
+	MethodSamplingCreateMessage = "sampling/createMessage"
+
+	// MethodElicitationCreate asks the client to collect input from the user.
+	MethodElicitationCreate = "elicitation/create"
+
+	// MethodLoggingMessage sends a log message to the client.
+	MethodLoggingMessage = "notifications/message"
 )
 
 // ContentBlock represents a piece of content in a tool response or prompt message.
@@ -51,6 +104,9 @@ type ContentBlock struct {
 
 	// Data is base64-encoded binary data (for type="blob").
 	Data string `json:"data,omitempty"`
+
+	// Resource is the embedded resource (for type="resource").
+	Resource *ResourceContent `json:"resource,omitempty"`
 }
 
 // TextContent creates a ContentBlock containing plain text.
@@ -64,5 +120,22 @@ type Implementation struct {
 	Version string `json:"version,omitempty"`
 }
 
+// PingParams are the optional parameters of a ping request.
+type PingParams struct {
+	// Meta is opaque request metadata, echoed back in the result.
+	Meta json.RawMessage `json:"_meta,omitempty"`
+}
+
 // PingResult is the response to a ping request.
-type PingResult struct{}
+type PingResult struct {
+	// Meta echoes the request's _meta, if any. A server may add a
+	// BuildInfo under the "buildInfo" key.
+	Meta json.RawMessage `json:"_meta,omitempty"`
+}
+
+// BuildInfo describes the build of a running server.
+type BuildInfo struct {
+	Version   string `json:"version,omitempty"`
+	Commit    string `json:"commit,omitempty"`
+	GoVersion string `json:"goVersion,omitempty"`
+}
@@ -1,5 +1,7 @@
 package protocol
 
+import "fmt"
+
 // Resource describes a resource available from the server.
 type Resource struct {
 	// URI uniquely identifies the resource.
@@ -13,6 +15,9 @@ type Resource struct {
 
 	// MimeType indicates the resource content type (optional).
 	MimeType string `json:"mimeType,omitempty"`
+
+	// Size is the size of the resource content in bytes, if known (optional).
+	Size int64 `json:"size,omitempty"`
 }
 
 // ResourcesListResult is the response to resources/list.
@@ -30,6 +35,31 @@ type ResourceReadResult struct {
 	Contents []ResourceContent `json:"contents"`
 }
 
+// ResourceSubscribeParams are the params of resources/subscribe and
+// resources/unsubscribe.
+type ResourceSubscribeParams struct {
+	URI string `json:"uri"`
+}
+
+// ResourceUpdatedNotification is the params of notifications/resources/updated.
+type ResourceUpdatedNotification struct {
+	URI string `json:"uri"`
+}
+
+// ResourceListDiff is the params of notifications/resources/list_diff.
+type ResourceListDiff struct {
+	// Added lists resources that are new or whose metadata changed.
+	Added []Resource `json:"added,omitempty"`
+
+	// Removed lists the URIs of resources that are gone.
+	Removed []string `json:"removed,omitempty"`
+}
+
+// IsEmpty reports whether the diff contains no changes.
+func (d ResourceListDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0
+}
+
 // ResourceContent holds the actual resource data.
 type ResourceContent struct {
 	// URI is the resource URI.
@@ -43,6 +73,65 @@ type ResourceContent struct {
 
 	// Blob contains base64-encoded binary content (mutually exclusive with Text).
 	Blob string `json:"blob,omitempty"`
+
+	// Meta carries extension data such as the selected line range (optional).
+	Meta *ResourceContentMeta `json:"_meta,omitempty"`
+}
+
+// ResourceContentMeta carries extension data on resource content.
+type ResourceContentMeta struct {
+	// StartLine and EndLine are the 1-based, inclusive lines returned for a
+	// URI with a #L<start>-L<end> fragment.
+	StartLine int `json:"startLine,omitempty"`
+	EndLine   int `json:"endLine,omitempty"`
+
+	// TotalLines is the number of lines in the full resource.
+	TotalLines int `json:"totalLines,omitempty"`
+}
+
+// Validate reports an error if both Text and Blob are set, or if neither is
+// set and there is no URI to identify the (empty) content.
+func (c ResourceContent) Validate() error {
+	if c.Text != "" && c.Blob != "" {
+		return fmt.Errorf("resource content %s: text and blob are mutually exclusive", c.URI)
+	}
+	if c.Text == "" && c.Blob == "" && c.URI == "" {
+		return fmt.Errorf("resource content has no text, blob, or uri")
+	}
+	return nil
+}
+
+// Validate validates each of the result's contents.
+func (r *ResourceReadResult) Validate() error {
+	for _, c := range r.Contents {
+		if err := c.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ResourceReadManyParams specifies the resources to read in a resources/read_many call.
+type ResourceReadManyParams struct {
+	URIs []string `json:"uris"`
+}
+
+// ResourceReadManyResult contains one entry per requested URI, in request order.
+type ResourceReadManyResult struct {
+	Results []ResourceReadManyEntry `json:"results"`
+}
+
+// ResourceReadManyEntry is the outcome of reading a single URI in a batch.
+// Exactly one of Contents or Error is set.
+type ResourceReadManyEntry struct {
+	// URI is the requested resource URI.
+	URI string `json:"uri"`
+
+	// Contents holds the resource data when the read succeeded.
+	Contents []ResourceContent `json:"contents,omitempty"`
+
+	// Error describes why the read failed (optional).
+	Error string `json:"error,omitempty"`
 }
 
 // ResourceTemplate describes a parameterized resource URI pattern.
@@ -0,0 +1,141 @@
+package protocol
+
+import "fmt"
+
+// SamplingMessage is a message in a sampling conversation.
+type SamplingMessage struct {
+	// Role is "user" or "assistant".
+	Role string `json:"role"`
+
+	// Content is the message content.
+	Content ContentBlock `json:"content"`
+}
+
+// CreateMessageParams are sent by the server to request a completion from
+// the client's LLM.
+type CreateMessageParams struct {
+	// Messages is the conversation to complete.
+	Messages []SamplingMessage `json:"messages"`
+
+	// SystemPrompt is an optional system prompt the client may use.
+	SystemPrompt string `json:"systemPrompt,omitempty"`
+
+	// MaxTokens bounds the length of the completion.
+	MaxTokens int `json:"maxTokens"`
+
+	// Temperature is the sampling temperature (optional).
+	Temperature *float64 `json:"temperature,omitempty"`
+
+	// StopSequences end the completion when generated (optional).
+	StopSequences []string `json:"stopSequences,omitempty"`
+
+	// ModelPreferences guide the client's choice of model (optional).
+	// Build them with NewModelPreferences.
+	ModelPreferences *ModelPreferences `json:"modelPreferences,omitempty"`
+}
+
+// ModelPreferences express the server's priorities for model selection
+// during sampling. The client makes the final choice.
+type ModelPreferences struct {
+	// Hints suggest models in order of preference. Clients may match them
+	// as substrings of model names or map them to equivalent models.
+	Hints []ModelHint `json:"hints,omitempty"`
+
+	// CostPriority, SpeedPriority, and IntelligencePriority weigh low cost,
+	// low latency, and capability, each from 0 (unimportant) to 1 (most
+	// important). Nil leaves the priority to the client.
+	CostPriority         *float64 `json:"costPriority,omitempty"`
+	SpeedPriority        *float64 `json:"speedPriority,omitempty"`
+	IntelligencePriority *float64 `json:"intelligencePriority,omitempty"`
+}
+
+// ModelHint names a model or model family, e.g. "claude-3-5-sonnet".
+type ModelHint struct {
+	Name string `json:"name,omitempty"`
+}
+
+// Validate reports an error if a priority is outside [0, 1].
+func (p *ModelPreferences) Validate() error {
+	for _, priority := range []struct {
+		name  string
+		value *float64
+	}{
+		{"costPriority", p.CostPriority},
+		{"speedPriority", p.SpeedPriority},
+		{"intelligencePriority", p.IntelligencePriority},
+	} {
+		if v := priority.value; v != nil && (*v < 0 || *v > 1) {
+			return fmt.Errorf("model preferences: %s %v is outside [0, 1]", priority.name, *v)
+		}
+	}
+	return nil
+}
+
+// ModelPreferencesBuilder provides an ergonomic API for constructing
+// ModelPreferences.
+type ModelPreferencesBuilder struct {
+	prefs ModelPreferences
+}
+
+// NewModelPreferences creates a builder for model preferences, e.g.
+//
+//	prefs, err := protocol.NewModelPreferences().
+//		PreferModel("claude-3-5-sonnet").
+//		Speed(0.8).
+//		Intelligence(0.9).
+//		Build()
+func NewModelPreferences() *ModelPreferencesBuilder {
+	return &ModelPreferencesBuilder{}
+}
+
+// PreferModel appends model hints, most preferred first.
+func (b *ModelPreferencesBuilder) PreferModel(names ...string) *ModelPreferencesBuilder {
+	for _, name := range names {
+		b.prefs.Hints = append(b.prefs.Hints, ModelHint{Name: name})
+	}
+	return b
+}
+
+// Cost sets the priority of low cost, from 0 to 1.
+func (b *ModelPreferencesBuilder) Cost(priority float64) *ModelPreferencesBuilder {
+	b.prefs.CostPriority = &priority
+	return b
+}
+
+// Speed sets the priority of low latency, from 0 to 1.
+func (b *ModelPreferencesBuilder) Speed(priority float64) *ModelPreferencesBuilder {
+	b.prefs.SpeedPriority = &priority
+	return b
+}
+
+// Intelligence sets the priority of model capability, from 0 to 1.
+func (b *ModelPreferencesBuilder) Intelligence(priority float64) *ModelPreferencesBuilder {
+	b.prefs.IntelligencePriority = &priority
+	return b
+}
+
+// Build returns the model preferences, or an error if a priority is
+// outside [0, 1].
+func (b *ModelPreferencesBuilder) Build() (*ModelPreferences, error) {
+	prefs := b.prefs
+	prefs.Hints = append([]ModelHint(nil), b.prefs.Hints...)
+	if err := prefs.Validate(); err != nil {
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+// CreateMessageResult is the client's response to sampling/createMessage.
+type CreateMessageResult struct {
+	// Role is the role of the generated message, normally "assistant".
+	Role string `json:"role"`
+
+	// Content is the generated content.
+	Content ContentBlock `json:"content"`
+
+	// Model is the name of the model that generated the message.
+	Model string `json:"model"`
+
+	// StopReason explains why generation stopped (optional).
+	StopReason string `json:"stopReason,omitempty"`
+}
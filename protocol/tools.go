@@ -12,6 +12,48 @@ type Tool struct {
 
 	// InputSchema is a JSON Schema describing the tool's input parameters.
 	InputSchema json.RawMessage `json:"inputSchema"`
+
+	// Annotations describe the tool's behavior to the client (optional).
+	Annotations *ToolAnnotations `json:"annotations,omitempty"`
+
+	// Meta carries extension metadata such as grouping hints (optional).
+	Meta *ToolMeta `json:"_meta,omitempty"`
+}
+
+// ToolMeta is extension metadata on a tool, sent under _meta so it stays
+// compatible with clients that do not understand it.
+type ToolMeta struct {
+	// Category is a single group the tool belongs to, e.g. "git" (optional).
+	Category string `json:"category,omitempty"`
+
+	// Tags are free-form labels clients may use to group or filter tools (optional).
+	Tags []string `json:"tags,omitempty"`
+}
+
+// ToolAnnotations are hints about a tool's behavior. They are advisory:
+// clients must not rely on them for security decisions about untrusted servers.
+type ToolAnnotations struct {
+	// Title is a human-readable title for the tool (optional).
+	Title string `json:"title,omitempty"`
+
+	// ReadOnlyHint indicates the tool does not modify its environment.
+	ReadOnlyHint *bool `json:"readOnlyHint,omitempty"`
+
+	// DestructiveHint indicates the tool may perform destructive updates.
+	DestructiveHint *bool `json:"destructiveHint,omitempty"`
+
+	// IdempotentHint indicates repeated calls with the same arguments have no
+	// additional effect.
+	IdempotentHint *bool `json:"idempotentHint,omitempty"`
+
+	// OpenWorldHint indicates the tool interacts with external entities.
+	OpenWorldHint *bool `json:"openWorldHint,omitempty"`
+}
+
+// IsReadOnly reports whether the tool is annotated with readOnlyHint: true.
+// Tools without annotations are not considered read-only.
+func (t Tool) IsReadOnly() bool {
+	return t.Annotations != nil && t.Annotations.ReadOnlyHint != nil && *t.Annotations.ReadOnlyHint
 }
 
 // ToolsListResult is the response to tools/list.
@@ -26,6 +68,9 @@ type ToolCallParams struct {
 
 	// Arguments are the JSON-encoded tool arguments.
 	Arguments json.RawMessage `json:"arguments,omitempty"`
+
+	// Meta carries request metadata such as a progress token (optional).
+	Meta *RequestMeta `json:"_meta,omitempty"`
 }
 
 // ToolCallResult is the result of invoking a tool.
@@ -33,10 +78,24 @@ type ToolCallResult struct {
 	// Content contains the tool's output.
 	Content []ContentBlock `json:"content"`
 
+	// StructuredContent is optional machine-readable output.
+	// It must marshal to a JSON object.
+	StructuredContent any `json:"structuredContent,omitempty"`
+
 	// IsError indicates whether the tool execution failed.
 	IsError bool `json:"isError,omitempty"`
 }
 
+// ToolErrorDetail is the machine-readable error carried in the structured
+// content of results built with ErrorResultWithCode or ErrorResultWithChain.
+type ToolErrorDetail struct {
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+
+	// Chain lists the messages of the wrapped errors, outermost first.
+	Chain []string `json:"chain,omitempty"`
+}
+
 // ErrorResult creates a ToolCallResult representing an error.
 func ErrorResult(msg string) *ToolCallResult {
 	return &ToolCallResult{
@@ -44,3 +103,119 @@ func ErrorResult(msg string) *ToolCallResult {
 		IsError: true,
 	}
 }
+
+// ErrorResultWithCode creates an error ToolCallResult that also carries a
+// machine-readable code, e.g. "not_found" or "permission_denied", as
+// structured content of the form {"error": {"code": ..., "message": ...}}.
+// The text block remains the human-readable fallback.
+func ErrorResultWithCode(code, msg string) *ToolCallResult {
+	result := ErrorResult(msg)
+	result.StructuredContent = map[string]any{
+		"error": ToolErrorDetail{Code: code, Message: msg},
+	}
+	return result
+}
+
+// ErrorResultFromErr creates an error ToolCallResult from err's message.
+// It returns nil if err is nil, so handlers can write:
+//
+//	if err != nil {
+//		return protocol.ErrorResultFromErr(err), nil
+//	}
+func ErrorResultFromErr(err error) *ToolCallResult {
+	if err == nil {
+		return nil
+	}
+	return ErrorResult(err.Error())
+}
+
+// ErrorResultWithChain is like ErrorResultFromErr but also carries the
+// messages of every error wrapped by err as structured content of the form
+// {"error": {"message": ..., "chain": [...]}}. It returns nil if err is nil.
+func ErrorResultWithChain(err error) *ToolCallResult {
+	if err == nil {
+		return nil
+	}
+	result := ErrorResult(err.Error())
+	result.StructuredContent = map[string]any{
+		"error": ToolErrorDetail{Message: err.Error(), Chain: errorChain(err)},
+	}
+	return result
+}
+
+// errorChain returns the messages of err and the errors it wraps, depth first.
+func errorChain(err error) []string {
+	var chain []string
+	var walk func(error)
+	walk = func(err error) {
+		if err == nil {
+			return
+		}
+		chain = append(chain, err.Error())
+		switch e := err.(type) {
+		case interface{ Unwrap() error }:
+			walk(e.Unwrap())
+		case interface{ Unwrap() []error }:
+			for _, inner := range e.Unwrap() {
+				walk(inner)
+			}
+		}
+	}
+	walk(err)
+	return chain
+}
+
+// MergeResults combines several tool results into one: content blocks are
+// concatenated in order, structured content objects are merged shallowly
+// (later keys win), and IsError is set if any input is an error. Nil inputs
+// are skipped. Structured content that does not encode to a JSON object is
+// dropped.
+func MergeResults(results ...*ToolCallResult) *ToolCallResult {
+	merged := &ToolCallResult{Content: []ContentBlock{}}
+	var structured map[string]any
+
+	for _, r := range results {
+		if r == nil {
+			continue
+		}
+
+		merged.Content = append(merged.Content, r.Content...)
+		merged.IsError = merged.IsError || r.IsError
+
+		if r.StructuredContent == nil {
+			continue
+		}
+		fields, ok := structuredFields(r.StructuredContent)
+		if !ok {
+			continue
+		}
+		if structured == nil {
+			structured = make(map[string]any)
+		}
+		for k, v := range fields {
+			structured[k] = v
+		}
+	}
+
+	if structured != nil {
+		merged.StructuredContent = structured
+	}
+	return merged
+}
+
+// structuredFields returns the top-level fields of v's JSON object encoding.
+func structuredFields(v any) (map[string]any, bool) {
+	if m, ok := v.(map[string]any); ok {
+		return m, true
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, false
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, false
+	}
+	return m, m != nil
+}
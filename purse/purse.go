@@ -1,6 +1,12 @@
 package purse
 
-import "sort"
+import (
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
 
 // Built-in tool names that purse-first can intercept.
 const (
@@ -13,26 +19,91 @@ const (
 )
 
 // ToolSuggestion is an MCP tool that can replace a built-in tool.
+// Server is only populated by query helpers such as FindByReplaces and is
+// omitted from mapping files, where the server is implied by the file.
 type ToolSuggestion struct {
 	Name    string `json:"name"`
 	UseWhen string `json:"use_when"`
+	Server  string `json:"server,omitempty"`
 }
 
 // Mapping is a single replacement rule declaring that an MCP server's tools
 // should be used instead of a built-in tool.
+//
+// Extensions (e.g. ".go") and Patterns (glob syntax, e.g. "*_test.go" or ".*")
+// restrict the mapping to matching files; a file matches if it satisfies
+// either list. A mapping with neither applies to all files.
 type Mapping struct {
 	Replaces   string           `json:"replaces"`
 	Extensions []string         `json:"extensions,omitempty"`
+	Patterns   []string         `json:"patterns,omitempty"`
 	Tools      []ToolSuggestion `json:"tools"`
 	Reason     string           `json:"reason"`
 }
 
+// MatchesFile reports whether the mapping applies to the given file path.
+// Patterns without a slash are matched against the base name; patterns with a
+// slash are matched against the whole slash-separated path.
+func (m Mapping) MatchesFile(filePath string) bool {
+	if len(m.Extensions) == 0 && len(m.Patterns) == 0 {
+		return true
+	}
+
+	if slices.Contains(m.Extensions, filepath.Ext(filePath)) {
+		return true
+	}
+
+	slashed := filepath.ToSlash(filePath)
+	for _, pattern := range m.Patterns {
+		target := slashed
+		if !strings.Contains(pattern, "/") {
+			target = path.Base(slashed)
+		}
+		if ok, _ := path.Match(pattern, target); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
 // MappingFile is the top-level structure written to disk and read by purse-first.
 type MappingFile struct {
 	Server   string    `json:"server"`
 	Mappings []Mapping `json:"mappings"`
 }
 
+// FindByReplaces returns every tool suggestion across the given mapping files
+// that replaces the given built-in tool, with Server set to the owning server.
+// Suggestions are returned in file order and deduplicated by server and name.
+func FindByReplaces(files []MappingFile, builtin string) []ToolSuggestion {
+	type key struct{ server, name string }
+
+	var result []ToolSuggestion
+	seen := make(map[key]bool)
+
+	for _, mf := range files {
+		for _, m := range mf.Mappings {
+			if m.Replaces != builtin {
+				continue
+			}
+
+			for _, ts := range m.Tools {
+				k := key{mf.Server, ts.Name}
+				if seen[k] {
+					continue
+				}
+				seen[k] = true
+
+				ts.Server = mf.Server
+				result = append(result, ts)
+			}
+		}
+	}
+
+	return result
+}
+
 // Plugin is a purse-first plugin manifest (plugin.json) that declares an MCP
 // server, its transport, optional hook notifications, and tool mappings.
 type Plugin struct {
@@ -44,6 +115,50 @@ type Plugin struct {
 	Mappings      []Mapping      `json:"mappings,omitempty"`
 }
 
+// CommandLine returns the command and arguments purse-first runs to start
+// the plugin's server.
+func (p Plugin) CommandLine() []string {
+	return append([]string{p.Command}, p.Args...)
+}
+
+// String renders the plugin's command line with POSIX shell quoting, suitable
+// for logging or pasting into a shell.
+func (p Plugin) String() string {
+	words := p.CommandLine()
+	quoted := make([]string, len(words))
+	for i, w := range words {
+		quoted[i] = shellQuote(w)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// shellQuote quotes s for a POSIX shell, leaving it bare when it contains
+// only characters that need no quoting.
+func shellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	safe := true
+	for _, r := range s {
+		if !isShellSafe(r) {
+			safe = false
+			break
+		}
+	}
+	if safe {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func isShellSafe(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return true
+	}
+	return strings.ContainsRune("_-+=@%:,./", r)
+}
+
 // Notification describes an HTTP POST to fire in response to a hook event.
 type Notification struct {
 	On       string           `json:"on"`
@@ -75,6 +190,7 @@ type MappingBuilder struct {
 type mappingEntry struct {
 	replaces   string
 	extensions []string
+	patterns   []string
 	tools      []ToolSuggestion
 	reason     string
 }
@@ -103,6 +219,13 @@ func (eb *MappingEntryBuilder) ForExtensions(exts ...string) *MappingEntryBuilde
 	return eb
 }
 
+// ForPatterns limits the mapping to files matching the given glob patterns,
+// in addition to any extensions.
+func (eb *MappingEntryBuilder) ForPatterns(patterns ...string) *MappingEntryBuilder {
+	eb.entry.patterns = append(eb.entry.patterns, patterns...)
+	return eb
+}
+
 // WithTool adds an MCP tool suggestion to the current mapping.
 func (eb *MappingEntryBuilder) WithTool(name, useWhen string) *MappingEntryBuilder {
 	eb.entry.tools = append(eb.entry.tools, ToolSuggestion{
@@ -130,6 +253,7 @@ func (b *MappingBuilder) Build() MappingFile {
 		mappings[i] = Mapping{
 			Replaces:   e.replaces,
 			Extensions: e.extensions,
+			Patterns:   e.patterns,
 			Tools:      e.tools,
 			Reason:     e.reason,
 		}
@@ -2,7 +2,11 @@ package purse
 
 import (
 	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
+
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
 )
 
 func TestMappingFileJSON(t *testing.T) {
@@ -315,3 +319,188 @@ func TestBuilderWireFormat(t *testing.T) {
 		t.Errorf("wire tool use_when = %v", tool["use_when"])
 	}
 }
+
+func TestFindByReplaces(t *testing.T) {
+	files := []MappingFile{
+		NewMappingBuilder("lux").
+			Replaces(BuiltinRead).ForExtensions(".go").WithTool("lsp_hover", "type info").
+			Replaces(BuiltinRead).ForExtensions(".py").WithTool("lsp_hover", "type info").
+			Replaces(BuiltinGrep).WithTool("lsp_references", "usages").
+			parent.Build(),
+		NewMappingBuilder("files").
+			Replaces(BuiltinRead).WithTool("read_file", "reading files").
+			parent.Build(),
+	}
+
+	got := FindByReplaces(files, BuiltinRead)
+	if len(got) != 2 {
+		t.Fatalf("len = %d, want 2: %+v", len(got), got)
+	}
+
+	if got[0].Server != "lux" || got[0].Name != "lsp_hover" {
+		t.Errorf("got[0] = %+v, want lux/lsp_hover", got[0])
+	}
+	if got[1].Server != "files" || got[1].Name != "read_file" {
+		t.Errorf("got[1] = %+v, want files/read_file", got[1])
+	}
+
+	if got := FindByReplaces(files, BuiltinBash); len(got) != 0 {
+		t.Errorf("expected no suggestions for Bash, got %+v", got)
+	}
+}
+
+func TestToolSuggestionServerOmitted(t *testing.T) {
+	data, err := json.Marshal(ToolSuggestion{Name: "read_file", UseWhen: "reading"})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	var raw map[string]json.RawMessage
+	json.Unmarshal(data, &raw)
+
+	if _, ok := raw["server"]; ok {
+		t.Error("server should be omitted when empty")
+	}
+}
+
+func TestPatternsRoundTrip(t *testing.T) {
+	mf := NewMappingBuilder("test-server").
+		Replaces(BuiltinRead).
+		ForExtensions(".go").
+		ForPatterns("*_test.go", ".*").
+		WithTool("read_test", "reading tests").
+		parent.Build()
+
+	data, err := json.Marshal(mf)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	var got MappingFile
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if len(got.Mappings[0].Patterns) != 2 {
+		t.Fatalf("patterns len = %d, want 2", len(got.Mappings[0].Patterns))
+	}
+}
+
+func TestPatternsOmitEmpty(t *testing.T) {
+	data, err := json.Marshal(Mapping{Replaces: BuiltinRead})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	var raw map[string]json.RawMessage
+	json.Unmarshal(data, &raw)
+
+	if _, ok := raw["patterns"]; ok {
+		t.Error("patterns should be omitted when nil")
+	}
+}
+
+func TestMappingMatchesFile(t *testing.T) {
+	m := Mapping{
+		Extensions: []string{".py"},
+		Patterns:   []string{"*_test.go", ".*", "docs/*.md"},
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"main.py", true},
+		{"pkg/foo_test.go", true},
+		{"pkg/foo.go", false},
+		{"/home/me/.bashrc", true},
+		{"docs/readme.md", true},
+		{"other/readme.md", false},
+	}
+
+	for _, tt := range tests {
+		if got := m.MatchesFile(tt.path); got != tt.want {
+			t.Errorf("MatchesFile(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	if !(Mapping{}).MatchesFile("anything.txt") {
+		t.Error("mapping without filters should match all files")
+	}
+}
+
+func TestPluginCommandLine(t *testing.T) {
+	p := Plugin{Command: "/bin/server", Args: []string{"--flag", "value"}}
+
+	got := p.CommandLine()
+	want := []string{"/bin/server", "--flag", "value"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+
+	got[1] = "changed"
+	if p.Args[0] != "--flag" {
+		t.Fatal("CommandLine must not alias Args")
+	}
+}
+
+func TestPluginStringQuotesArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{nil, "/bin/server"},
+		{[]string{"--dir", "/tmp/my dir"}, "/bin/server --dir '/tmp/my dir'"},
+		{[]string{"it's"}, `/bin/server 'it'\''s'`},
+		{[]string{`say "hi"`}, `/bin/server 'say "hi"'`},
+		{[]string{""}, "/bin/server ''"},
+		{[]string{"--opt=a,b:c"}, "/bin/server --opt=a,b:c"},
+		{[]string{"$HOME"}, "/bin/server '$HOME'"},
+	}
+
+	for _, tt := range tests {
+		p := Plugin{Command: "/bin/server", Args: tt.args}
+		if got := p.String(); got != tt.want {
+			t.Errorf("args %q: expected %s, got %s", tt.args, tt.want, got)
+		}
+	}
+}
+
+func TestValidateAgainstTools(t *testing.T) {
+	b := NewMappingBuilder("srv")
+	b.Replaces(BuiltinRead).ForExtensions(".go").WithTool("lsp_hover", "types").WithTool("lsp_hovr", "typo").
+		Replaces(BuiltinEdit).WithTool("lsp_rename", "renames").
+		Replaces(BuiltinGrep).WithTool("lsp_refs", "references")
+	mf := b.Build()
+	tools := []protocol.Tool{{Name: "lsp_hover"}, {Name: "lsp_rename"}, {Name: "lsp_refs"}}
+
+	errs := ValidateAgainstTools(mf, tools)
+	if len(errs) != 2 {
+		t.Fatalf("errs = %v, want 2", errs)
+	}
+
+	var unknown, unfiltered int
+	for _, err := range errs {
+		switch {
+		case errors.Is(err, ErrUnknownTool):
+			unknown++
+			if !strings.Contains(err.Error(), "lsp_hovr") {
+				t.Errorf("unknown tool error %q does not name the tool", err)
+			}
+		case errors.Is(err, ErrUnfilteredMapping):
+			unfiltered++
+		}
+	}
+	if unknown != 1 || unfiltered != 1 {
+		t.Fatalf("unknown = %d, unfiltered = %d, want 1 each: %v", unknown, unfiltered, errs)
+	}
+
+	if errs := ValidateAgainstTools(MappingFile{Server: "srv"}, nil); errs != nil {
+		t.Fatalf("empty mapping file: errs = %v, want nil", errs)
+	}
+}
@@ -0,0 +1,55 @@
+package purse
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+var (
+	// ErrUnknownTool is reported by ValidateAgainstTools for a suggested
+	// tool the server does not register.
+	ErrUnknownTool = errors.New("suggested tool is not registered")
+
+	// ErrUnfilteredMapping is reported by ValidateAgainstTools for a mapping
+	// that replaces a file-oriented built-in without any Extensions or
+	// Patterns, which makes it apply to every file.
+	ErrUnfilteredMapping = errors.New("mapping replaces a file built-in without an extension or pattern filter")
+)
+
+// fileBuiltins are the built-in tools that act on a single file, whose
+// mappings are normally scoped to the file types the server understands.
+var fileBuiltins = map[string]bool{
+	BuiltinRead:  true,
+	BuiltinEdit:  true,
+	BuiltinWrite: true,
+}
+
+// ValidateAgainstTools checks mf against the tools a server actually
+// registers, typically the result of ToolProvider.ListTools. It reports a
+// suggestion naming an unregistered tool with an error wrapping
+// ErrUnknownTool, and a mapping that replaces Read, Edit, or Write for all
+// files with one wrapping ErrUnfilteredMapping; use errors.Is to tell
+// mistakes from warnings. It returns nil if mf is consistent.
+func ValidateAgainstTools(mf MappingFile, tools []protocol.Tool) []error {
+	registered := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		registered[tool.Name] = true
+	}
+
+	var errs []error
+	for i, m := range mf.Mappings {
+		for _, ts := range m.Tools {
+			if !registered[ts.Name] {
+				errs = append(errs, fmt.Errorf("mapping %d (replaces %s): %w: %s", i, m.Replaces, ErrUnknownTool, ts.Name))
+			}
+		}
+
+		if fileBuiltins[m.Replaces] && len(m.Extensions) == 0 && len(m.Patterns) == 0 {
+			errs = append(errs, fmt.Errorf("mapping %d (replaces %s): %w", i, m.Replaces, ErrUnfilteredMapping))
+		}
+	}
+
+	return errs
+}
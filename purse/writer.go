@@ -14,49 +14,179 @@ func xdgStateHome() string {
 	return filepath.Join(home, ".local", "state")
 }
 
-func writeMappingFile(dir string, mf MappingFile) error {
-	if err := os.MkdirAll(dir, 0o755); err != nil {
+// GlobalDir returns the resolved global purse-first directory,
+// $XDG_STATE_HOME/purse-first, falling back to ~/.local/state/purse-first.
+func GlobalDir() string {
+	return filepath.Join(xdgStateHome(), "purse-first")
+}
+
+// writeFileAtomic writes data to a temp file in the same directory as path and
+// renames it over path, so readers see either the old or the new complete file.
+func writeFileAtomic(path string, data []byte) error {
+	dir, base := filepath.Split(path)
+
+	tmp, err := os.CreateTemp(dir, "."+base+".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
 		return err
 	}
 
-	data, err := json.MarshalIndent(mf, "", "  ")
+	return os.Rename(tmpName, path)
+}
+
+// WriteOptions controls how purse files are formatted. The zero value writes
+// two-space indented JSON, which is what the package-level functions use.
+// Every file ends with a trailing newline.
+type WriteOptions struct {
+	// Indent is the indentation string, e.g. "\t". Defaults to two spaces.
+	Indent string
+
+	// Compact writes each file as a single line, ignoring Indent.
+	Compact bool
+}
+
+func (o WriteOptions) marshal(v any) ([]byte, error) {
+	var data []byte
+	var err error
+	if o.Compact {
+		data, err = json.Marshal(v)
+	} else {
+		indent := o.Indent
+		if indent == "" {
+			indent = "  "
+		}
+		data, err = json.MarshalIndent(v, "", indent)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return append(data, '\n'), nil
+}
+
+func (o WriteOptions) renderMappingFile(dir string, mf MappingFile) ([]byte, string, error) {
+	data, err := o.marshal(mf)
 	if err != nil {
+		return nil, "", err
+	}
+
+	return data, filepath.Join(dir, mf.Server+".json"), nil
+}
+
+func writeRendered(data []byte, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
 		return err
 	}
 
-	data = append(data, '\n')
+	return writeFileAtomic(path, data)
+}
+
+// RenderGlobal returns the JSON that WriteGlobal would write and the path it
+// would write to, without touching the filesystem.
+func RenderGlobal(mf MappingFile) ([]byte, string, error) {
+	return WriteOptions{}.RenderGlobal(mf)
+}
 
-	return os.WriteFile(filepath.Join(dir, mf.Server+".json"), data, 0o644)
+// RenderProject returns the JSON that WriteProject would write and the path it
+// would write to, without touching the filesystem.
+func RenderProject(projectDir string, mf MappingFile) ([]byte, string, error) {
+	return WriteOptions{}.RenderProject(projectDir, mf)
+}
+
+// RenderPlugin returns the JSON that WritePlugin would write and the path it
+// would write to, without touching the filesystem.
+func RenderPlugin(dir string, p Plugin) ([]byte, string, error) {
+	return WriteOptions{}.RenderPlugin(dir, p)
 }
 
 // WriteGlobal writes the mapping file to the global purse-first directory
 // at $XDG_STATE_HOME/purse-first/{server}.json.
 func WriteGlobal(mf MappingFile) error {
-	dir := filepath.Join(xdgStateHome(), "purse-first")
-	return writeMappingFile(dir, mf)
+	return WriteOptions{}.WriteGlobal(mf)
+}
+
+// WriteGlobalTo writes the mapping file to {baseDir}/{server}.json, for callers
+// that need to control the global directory instead of relying on XDG_STATE_HOME.
+func WriteGlobalTo(baseDir string, mf MappingFile) error {
+	return WriteOptions{}.WriteGlobalTo(baseDir, mf)
 }
 
 // WriteProject writes the mapping file to a project-local purse-first directory
 // at {projectDir}/.purse-first/{server}.json.
 func WriteProject(projectDir string, mf MappingFile) error {
-	dir := filepath.Join(projectDir, ".purse-first")
-	return writeMappingFile(dir, mf)
+	return WriteOptions{}.WriteProject(projectDir, mf)
 }
 
 // WritePlugin writes a plugin manifest to {dir}/{p.Name}/plugin.json.
 // This is used during nix postInstall to generate share/purse-first/<name>/plugin.json.
 func WritePlugin(dir string, p Plugin) error {
-	pluginDir := filepath.Join(dir, p.Name)
-	if err := os.MkdirAll(pluginDir, 0o755); err != nil {
-		return err
+	return WriteOptions{}.WritePlugin(dir, p)
+}
+
+// RenderGlobal is like the package-level RenderGlobal, formatted per o.
+func (o WriteOptions) RenderGlobal(mf MappingFile) ([]byte, string, error) {
+	return o.renderMappingFile(GlobalDir(), mf)
+}
+
+// RenderProject is like the package-level RenderProject, formatted per o.
+func (o WriteOptions) RenderProject(projectDir string, mf MappingFile) ([]byte, string, error) {
+	return o.renderMappingFile(filepath.Join(projectDir, ".purse-first"), mf)
+}
+
+// RenderPlugin is like the package-level RenderPlugin, formatted per o.
+func (o WriteOptions) RenderPlugin(dir string, p Plugin) ([]byte, string, error) {
+	data, err := o.marshal(p)
+	if err != nil {
+		return nil, "", err
 	}
 
-	data, err := json.MarshalIndent(p, "", "  ")
+	return data, filepath.Join(dir, p.Name, "plugin.json"), nil
+}
+
+// WriteGlobal is like the package-level WriteGlobal, formatted per o.
+func (o WriteOptions) WriteGlobal(mf MappingFile) error {
+	return o.WriteGlobalTo(GlobalDir(), mf)
+}
+
+// WriteGlobalTo is like the package-level WriteGlobalTo, formatted per o.
+func (o WriteOptions) WriteGlobalTo(baseDir string, mf MappingFile) error {
+	data, path, err := o.renderMappingFile(baseDir, mf)
 	if err != nil {
 		return err
 	}
+	return writeRendered(data, path)
+}
 
-	data = append(data, '\n')
+// WriteProject is like the package-level WriteProject, formatted per o.
+func (o WriteOptions) WriteProject(projectDir string, mf MappingFile) error {
+	data, path, err := o.RenderProject(projectDir, mf)
+	if err != nil {
+		return err
+	}
+	return writeRendered(data, path)
+}
 
-	return os.WriteFile(filepath.Join(pluginDir, "plugin.json"), data, 0o644)
+// WritePlugin is like the package-level WritePlugin, formatted per o.
+func (o WriteOptions) WritePlugin(dir string, p Plugin) error {
+	data, path, err := o.RenderPlugin(dir, p)
+	if err != nil {
+		return err
+	}
+	return writeRendered(data, path)
 }
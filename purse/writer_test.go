@@ -147,3 +147,146 @@ func TestWriteGlobal(t *testing.T) {
 		t.Errorf("server = %q, want %q", got.Server, "global-server")
 	}
 }
+
+func TestWriteProjectReplacesExisting(t *testing.T) {
+	dir := t.TempDir()
+
+	first := MappingFile{Server: "test-server", Mappings: []Mapping{{Replaces: BuiltinRead}}}
+	if err := WriteProject(dir, first); err != nil {
+		t.Fatalf("WriteProject: %v", err)
+	}
+
+	second := MappingFile{Server: "test-server", Mappings: []Mapping{{Replaces: BuiltinGrep}}}
+	if err := WriteProject(dir, second); err != nil {
+		t.Fatalf("WriteProject: %v", err)
+	}
+
+	path := filepath.Join(dir, ".purse-first", "test-server.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	var got MappingFile
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if got.Mappings[0].Replaces != BuiltinGrep {
+		t.Errorf("replaces = %q, want %q", got.Mappings[0].Replaces, BuiltinGrep)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o644 {
+		t.Errorf("perm = %o, want 644", perm)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, ".purse-first"))
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the mapping file, found %d entries", len(entries))
+	}
+}
+
+func TestRenderProjectDoesNotWrite(t *testing.T) {
+	dir := t.TempDir()
+
+	mf := MappingFile{Server: "test-server", Mappings: []Mapping{{Replaces: BuiltinRead}}}
+
+	data, path, err := RenderProject(dir, mf)
+	if err != nil {
+		t.Fatalf("RenderProject: %v", err)
+	}
+
+	want := filepath.Join(dir, ".purse-first", "test-server.json")
+	if path != want {
+		t.Errorf("path = %q, want %q", path, want)
+	}
+
+	if data[len(data)-1] != '\n' {
+		t.Error("expected trailing newline")
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, ".purse-first")); !os.IsNotExist(err) {
+		t.Error("expected RenderProject not to create the directory")
+	}
+
+	if err := WriteProject(dir, mf); err != nil {
+		t.Fatalf("WriteProject: %v", err)
+	}
+
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(written) != string(data) {
+		t.Errorf("written content differs from rendered content")
+	}
+}
+
+func TestRenderPluginPath(t *testing.T) {
+	p := Plugin{Name: "my-plugin", Type: "stdio", Command: "my-plugin"}
+
+	_, path, err := RenderPlugin("/share/purse-first", p)
+	if err != nil {
+		t.Fatalf("RenderPlugin: %v", err)
+	}
+
+	want := filepath.Join("/share/purse-first", "my-plugin", "plugin.json")
+	if path != want {
+		t.Errorf("path = %q, want %q", path, want)
+	}
+}
+
+func TestWriteGlobalTo(t *testing.T) {
+	dir := t.TempDir()
+
+	mf := MappingFile{Server: "sandboxed", Mappings: []Mapping{{Replaces: BuiltinRead}}}
+	if err := WriteGlobalTo(dir, mf); err != nil {
+		t.Fatalf("WriteGlobalTo: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "sandboxed.json")); err != nil {
+		t.Errorf("expected mapping file in base dir: %v", err)
+	}
+}
+
+func TestGlobalDir(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", dir)
+
+	want := filepath.Join(dir, "purse-first")
+	if got := GlobalDir(); got != want {
+		t.Errorf("GlobalDir() = %q, want %q", got, want)
+	}
+}
+
+func TestWriteOptionsFormatting(t *testing.T) {
+	mf := MappingFile{Server: "test-server"}
+
+	tests := []struct {
+		name string
+		opts WriteOptions
+		want string
+	}{
+		{"default", WriteOptions{}, "{\n  \"server\": \"test-server\",\n  \"mappings\": null\n}\n"},
+		{"tabs", WriteOptions{Indent: "\t"}, "{\n\t\"server\": \"test-server\",\n\t\"mappings\": null\n}\n"},
+		{"compact", WriteOptions{Compact: true, Indent: "\t"}, "{\"server\":\"test-server\",\"mappings\":null}\n"},
+	}
+
+	for _, tt := range tests {
+		data, _, err := tt.opts.RenderProject(t.TempDir(), mf)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		if string(data) != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.want, string(data))
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package server
+
+import (
+	"runtime"
+	"runtime/debug"
+
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+// ReadBuildInfo describes the running binary for Options.BuildInfo: the main
+// module's version, the VCS revision it was built from, and the Go version.
+// Fields the binary does not record are left empty.
+func ReadBuildInfo() *protocol.BuildInfo {
+	info := &protocol.BuildInfo{GoVersion: runtime.Version()}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	if v := bi.Main.Version; v != "" && v != "(devel)" {
+		info.Version = v
+	}
+	for _, s := range bi.Settings {
+		if s.Key == "vcs.revision" {
+			info.Commit = s.Value
+		}
+	}
+	return info
+}
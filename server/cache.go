@@ -0,0 +1,113 @@
+package server
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+// resourceCache memoizes a resource reader's result for a TTL.
+type resourceCache struct {
+	reader ResourceReader
+	ttl    time.Duration
+
+	// sem serializes refreshes so concurrent reads of an expired entry wait
+	// for a single call to the reader instead of each making their own.
+	sem chan struct{}
+
+	mu      sync.Mutex
+	result  *protocol.ResourceReadResult
+	expires time.Time
+}
+
+func newResourceCache(reader ResourceReader, ttl time.Duration) *resourceCache {
+	return &resourceCache{
+		reader: reader,
+		ttl:    ttl,
+		sem:    make(chan struct{}, 1),
+	}
+}
+
+func (c *resourceCache) cached() *protocol.ResourceReadResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.result == nil || time.Now().After(c.expires) {
+		return nil
+	}
+	return c.result
+}
+
+func (c *resourceCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.result = nil
+}
+
+// read returns the cached result or refreshes it. Errors are not cached.
+func (c *resourceCache) read(ctx context.Context, uri string) (*protocol.ResourceReadResult, error) {
+	if result := c.cached(); result != nil {
+		return copyReadResult(result), nil
+	}
+
+	select {
+	case c.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-c.sem }()
+
+	// Another caller may have refreshed the entry while we waited.
+	if result := c.cached(); result != nil {
+		return copyReadResult(result), nil
+	}
+
+	result, err := c.reader(ctx, uri)
+	if err != nil || result == nil {
+		return result, err
+	}
+
+	c.mu.Lock()
+	c.result = result
+	c.expires = time.Now().Add(c.ttl)
+	c.mu.Unlock()
+
+	return copyReadResult(result), nil
+}
+
+// copyReadResult returns a copy whose Contents can be modified without
+// affecting the cache.
+func copyReadResult(r *protocol.ResourceReadResult) *protocol.ResourceReadResult {
+	cp := *r
+	cp.Contents = slices.Clone(r.Contents)
+	return &cp
+}
+
+// RegisterCached adds a static resource whose reader's result is reused for
+// ttl before the reader is called again. Concurrent reads of an expired
+// entry share a single refresh. Failed reads are not cached. A zero ttl
+// disables caching, making this equivalent to RegisterResource.
+func (r *ResourceRegistry) RegisterCached(resource protocol.Resource, reader ResourceReader, ttl time.Duration) {
+	if ttl <= 0 {
+		r.RegisterResource(resource, reader)
+		return
+	}
+
+	cache := newResourceCache(reader, ttl)
+	r.register(resource, cache.read, cache)
+}
+
+// InvalidateResource discards the cached content of a resource registered
+// with RegisterCached, so the next read calls its reader. It does nothing for
+// other resources.
+func (r *ResourceRegistry) InvalidateResource(uri string) {
+	r.mu.RLock()
+	cache := r.caches[uri]
+	r.mu.RUnlock()
+
+	if cache != nil {
+		cache.invalidate()
+	}
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+func TestRegisterCachedReusesResultUntilInvalidated(t *testing.T) {
+	var calls atomic.Int32
+	reader := func(ctx context.Context, uri string) (*protocol.ResourceReadResult, error) {
+		calls.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		return &protocol.ResourceReadResult{
+			Contents: []protocol.ResourceContent{{URI: uri, Text: "data"}},
+		}, nil
+	}
+
+	r := NewResourceRegistry()
+	r.RegisterCached(protocol.Resource{URI: "test://slow", Name: "slow"}, reader, time.Hour)
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := r.ReadResource(context.Background(), "test://slow"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("reader called %d times, want 1", n)
+	}
+
+	r.InvalidateResource("test://slow")
+	if _, err := r.ReadResource(context.Background(), "test://slow"); err != nil {
+		t.Fatal(err)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Fatalf("reader called %d times after invalidation, want 2", n)
+	}
+}
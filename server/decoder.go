@@ -0,0 +1,36 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+// ArgumentDecoder normalizes a tool's raw arguments before its handler sees
+// them, e.g. to repair slightly malformed JSON or to expand a form-encoded
+// string into an object.
+type ArgumentDecoder func(args json.RawMessage) (json.RawMessage, error)
+
+// RegisterWithDecoder adds a tool whose arguments pass through decoder
+// before reaching handler. A nil decoder leaves them unchanged. The decoder
+// is invisible to clients: the tool is listed with schema as usual. Decoder
+// errors fail the call with ErrInvalidParams.
+// Like Register, it fails with ErrDuplicateTool if the name is taken.
+func (r *ToolRegistry) RegisterWithDecoder(name, description string, schema json.RawMessage, decoder ArgumentDecoder, handler ToolHandler, opts ...ToolOption) error {
+	if decoder == nil {
+		return r.Register(name, description, schema, handler, opts...)
+	}
+	return r.Register(name, description, schema, decodingHandler(name, decoder, handler), opts...)
+}
+
+func decodingHandler(name string, decoder ArgumentDecoder, handler ToolHandler) ToolHandler {
+	return func(ctx context.Context, args json.RawMessage) (*protocol.ToolCallResult, error) {
+		decoded, err := decoder(args)
+		if err != nil {
+			return nil, fmt.Errorf("%w: decoding arguments of %s: %v", ErrInvalidParams, name, err)
+		}
+		return handler(ctx, decoded)
+	}
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+// formDecoder expands {"query": "a=1&b=2"} into {"a":"1","b":"2"}.
+func formDecoder(args json.RawMessage) (json.RawMessage, error) {
+	var in struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal(args, &in); err != nil {
+		return nil, err
+	}
+	values, err := url.ParseQuery(in.Query)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]string, len(values))
+	for k := range values {
+		out[k] = values.Get(k)
+	}
+	return json.Marshal(out)
+}
+
+func TestRegisterWithDecoder(t *testing.T) {
+	reg := NewToolRegistry()
+	var got json.RawMessage
+	err := reg.RegisterWithDecoder("search", "", json.RawMessage(`{"type":"object"}`), formDecoder,
+		func(ctx context.Context, args json.RawMessage) (*protocol.ToolCallResult, error) {
+			got = args
+			return &protocol.ToolCallResult{}, nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := reg.CallTool(context.Background(), "search", json.RawMessage(`{"query":"a=1&b=2"}`)); err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if want := `{"a":"1","b":"2"}`; string(got) != want {
+		t.Errorf("handler args = %s, want %s", got, want)
+	}
+
+	_, err = reg.CallTool(context.Background(), "search", json.RawMessage(`{"query":"%zz"}`))
+	if !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("err = %v, want ErrInvalidParams", err)
+	}
+}
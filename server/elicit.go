@@ -0,0 +1,37 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+// ErrElicitationUnsupported is returned by Elicit when the client did not
+// advertise the elicitation capability.
+var ErrElicitationUnsupported = errors.New("client does not support elicitation")
+
+// Elicit asks the client to collect structured input from the user and waits
+// for the answer. Check the result's Action: Content is only set when the
+// user accepted.
+func (s *Server) Elicit(ctx context.Context, params protocol.ElicitParams) (*protocol.ElicitResult, error) {
+	sess := s.session()
+
+	client := sess.handler.client.Load()
+	if client == nil || client.Capabilities.Elicitation == nil {
+		return nil, ErrElicitationUnsupported
+	}
+
+	raw, err := sess.request(ctx, protocol.MethodElicitationCreate, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var result protocol.ElicitResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("parsing elicitation/create result: %w", err)
+	}
+	return &result, nil
+}
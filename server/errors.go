@@ -0,0 +1,55 @@
+package server
+
+import "errors"
+
+var (
+	// ErrServerNameRequired is returned by New when Options.ServerName is empty.
+	ErrServerNameRequired = errors.New("server name is required")
+
+	// ErrDuplicateTool is returned when a tool name is registered twice or
+	// provided by more than one provider.
+	ErrDuplicateTool = errors.New("duplicate tool")
+
+	// ErrUnknownTool is returned when an operation names a tool that is not
+	// registered. A tools/call error wrapping it is reported to the client as
+	// InvalidParams.
+	ErrUnknownTool = errors.New("unknown tool")
+
+	// ErrUnknownResource is returned when an operation names a resource that
+	// is not registered. A request error wrapping it is reported to the
+	// client as InvalidParams.
+	ErrUnknownResource = errors.New("unknown resource")
+
+	// ErrUnknownPrompt is returned when an operation names a prompt that is
+	// not registered. A request error wrapping it is reported to the client
+	// as InvalidParams.
+	ErrUnknownPrompt = errors.New("unknown prompt")
+
+	// ErrInvalidParams marks an error caused by invalid request parameters.
+	// Providers can wrap it so the client receives an InvalidParams error
+	// instead of an InternalError.
+	ErrInvalidParams = errors.New("invalid params")
+)
+
+// ToolError is a tool failure with a machine-readable code. A tool handler
+// returning one produces an error result built with
+// protocol.ErrorResultWithCode rather than a JSON-RPC error.
+type ToolError struct {
+	// Code identifies the kind of failure, e.g. "not_found".
+	Code string
+
+	// Message describes the failure. If empty, Err's message is used.
+	Message string
+
+	// Err is the underlying error (optional).
+	Err error
+}
+
+func (e *ToolError) Error() string {
+	if e.Message == "" && e.Err != nil {
+		return e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *ToolError) Unwrap() error { return e.Err }
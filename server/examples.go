@@ -0,0 +1,70 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+// WithExamples embeds example arguments in the tool's input schema under the
+// JSON Schema "examples" keyword, so clients can show sample invocations.
+// Register rejects examples that are not JSON objects, that omit a property
+// listed in the schema's "required", or that set a property the schema does
+// not declare when "additionalProperties" is false. Other schema keywords are
+// not checked.
+func WithExamples(examples ...json.RawMessage) ToolOption {
+	return func(t *protocol.Tool) {
+		var schema map[string]json.RawMessage
+		if err := json.Unmarshal(t.InputSchema, &schema); err != nil || schema == nil {
+			schema = map[string]json.RawMessage{"type": json.RawMessage(`"object"`)}
+		}
+
+		data, err := json.Marshal(examples)
+		if err != nil {
+			return
+		}
+		schema["examples"] = data
+
+		if updated, err := json.Marshal(schema); err == nil {
+			t.InputSchema = updated
+		}
+	}
+}
+
+// validateExamples checks the "examples" of an input schema against the
+// schema's "required" and "additionalProperties": false constraints.
+func validateExamples(inputSchema json.RawMessage) error {
+	var schema struct {
+		Required             []string                   `json:"required"`
+		Properties           map[string]json.RawMessage `json:"properties"`
+		AdditionalProperties *bool                      `json:"additionalProperties"`
+		Examples             []json.RawMessage          `json:"examples"`
+	}
+	if err := json.Unmarshal(inputSchema, &schema); err != nil {
+		return nil
+	}
+
+	for i, example := range schema.Examples {
+		var args map[string]json.RawMessage
+		if err := json.Unmarshal(example, &args); err != nil || args == nil {
+			return fmt.Errorf("example %d: not a JSON object", i)
+		}
+
+		for _, name := range schema.Required {
+			if _, ok := args[name]; !ok {
+				return fmt.Errorf("example %d: missing required property %s", i, name)
+			}
+		}
+
+		if schema.AdditionalProperties != nil && !*schema.AdditionalProperties {
+			for name := range args {
+				if _, ok := schema.Properties[name]; !ok {
+					return fmt.Errorf("example %d: undeclared property %s", i, name)
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,316 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+// FSOptions configures FileSystemResources.
+//
+// Patterns use path.Match syntax. A pattern without a slash is matched against
+// the file's base name; a pattern with a slash is matched against the file's
+// slash-separated path relative to the root.
+type FSOptions struct {
+	// Include limits listing and reading to files matching at least one pattern.
+	// If empty, all files are included.
+	Include []string
+
+	// Exclude skips files (and directories) matching any pattern.
+	// Exclude takes priority over Include.
+	Exclude []string
+}
+
+// fileSystemResources serves the regular files below root as file:// resources.
+type fileSystemResources struct {
+	root string
+	opts FSOptions
+}
+
+// FileSystemResources returns a ResourceProvider exposing the files below root.
+// Each file is listed with a file:// URI of its absolute path, and reads are
+// refused for URIs outside root (including via symlinks) or filtered out by opts.
+func FileSystemResources(root string, opts FSOptions) ResourceProvider {
+	if abs, err := filepath.Abs(root); err == nil {
+		root = abs
+	}
+	return &fileSystemResources{root: filepath.Clean(root), opts: opts}
+}
+
+// ListResources implements ResourceProvider.
+func (p *fileSystemResources) ListResources(ctx context.Context) ([]protocol.Resource, error) {
+	var resources []protocol.Resource
+
+	err := filepath.WalkDir(p.root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(p.root, name)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if matchesAny(p.opts.Exclude, rel) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !d.Type().IsRegular() || !p.included(rel) {
+			return nil
+		}
+
+		resources = append(resources, protocol.Resource{
+			URI:      fileURI(name),
+			Name:     rel,
+			MimeType: mime.TypeByExtension(path.Ext(rel)),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing %s: %w", p.root, err)
+	}
+
+	return resources, nil
+}
+
+// ReadResource implements ResourceProvider.
+func (p *fileSystemResources) ReadResource(ctx context.Context, uri string) (*protocol.ResourceReadResult, error) {
+	name, rel, err := p.resolve(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("reading resource %s: %w", uri, err)
+	}
+
+	return &protocol.ResourceReadResult{
+		Contents: []protocol.ResourceContent{fileContent(uri, rel, data)},
+	}, nil
+}
+
+// ListResourceTemplates implements ResourceProvider.
+func (p *fileSystemResources) ListResourceTemplates(ctx context.Context) ([]protocol.ResourceTemplate, error) {
+	return nil, nil
+}
+
+// resolve maps a file:// URI to a path below root, returning the filesystem path
+// and the slash-separated path relative to root.
+func (p *fileSystemResources) resolve(uri string) (string, string, error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return "", "", fmt.Errorf("%w: %s", ErrUnknownResource, uri)
+	}
+
+	name := filepath.Clean(filepath.FromSlash(u.Path))
+	if !within(p.root, name) {
+		return "", "", fmt.Errorf("resource outside root: %s", uri)
+	}
+
+	// Guard against symlinks that point outside root.
+	realRoot, err := filepath.EvalSymlinks(p.root)
+	if err != nil {
+		return "", "", fmt.Errorf("resolving root: %w", err)
+	}
+	realName, err := filepath.EvalSymlinks(name)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %s", ErrUnknownResource, uri)
+	}
+	if !within(realRoot, realName) {
+		return "", "", fmt.Errorf("resource outside root: %s", uri)
+	}
+
+	rel, err := filepath.Rel(p.root, name)
+	if err != nil {
+		return "", "", err
+	}
+	rel = filepath.ToSlash(rel)
+
+	// A symlink must not reach into a directory the listing skips.
+	realRel, err := filepath.Rel(realRoot, realName)
+	if err != nil {
+		return "", "", err
+	}
+
+	if !p.included(rel) || p.inExcludedDir(rel) || p.inExcludedDir(filepath.ToSlash(realRel)) {
+		return "", "", fmt.Errorf("%w: %s", ErrUnknownResource, uri)
+	}
+
+	return name, rel, nil
+}
+
+// inExcludedDir reports whether any directory containing rel matches
+// Exclude, i.e. whether ListResources would have skipped it.
+func (p *fileSystemResources) inExcludedDir(rel string) bool {
+	for dir := path.Dir(rel); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if matchesAny(p.opts.Exclude, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *fileSystemResources) included(rel string) bool {
+	if matchesAny(p.opts.Exclude, rel) {
+		return false
+	}
+	return len(p.opts.Include) == 0 || matchesAny(p.opts.Include, rel)
+}
+
+// within reports whether name is root or a descendant of root.
+func within(root, name string) bool {
+	rel, err := filepath.Rel(root, name)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func matchesAny(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		target := rel
+		if !strings.Contains(pattern, "/") {
+			target = path.Base(rel)
+		}
+		if ok, _ := path.Match(pattern, target); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func fileURI(name string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(name)}).String()
+}
+
+// fileContent builds a ResourceContent for file data, detecting the MIME type
+// from the extension and falling back to content sniffing.
+func fileContent(uri, name string, data []byte) protocol.ResourceContent {
+	return bytesContent(uri, mime.TypeByExtension(path.Ext(name)), data)
+}
+
+// bytesContent builds a ResourceContent for data of the given MIME type,
+// sniffing the type if it is empty. Textual content is returned as Text;
+// anything else is base64-encoded into Blob.
+func bytesContent(uri, mimeType string, data []byte) protocol.ResourceContent {
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+
+	content := protocol.ResourceContent{URI: uri, MimeType: mimeType}
+	if isTextMime(mimeType) && utf8.Valid(data) {
+		content.Text = string(data)
+	} else {
+		content.Blob = base64.StdEncoding.EncodeToString(data)
+	}
+
+	return content
+}
+
+func isTextMime(mimeType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(mimeType)
+	if strings.HasPrefix(mediaType, "text/") {
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/xml", "application/javascript",
+		"application/x-sh", "application/toml", "application/yaml":
+		return true
+	}
+	return strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml")
+}
+
+// fsResources serves the regular files of an fs.FS as resources.
+type fsResources struct {
+	fsys   fs.FS
+	prefix string
+}
+
+// FSResources returns a ResourceProvider exposing the regular files of fsys,
+// such as an embed.FS, with URIs formed by appending each file's
+// slash-separated path to uriPrefix, e.g. "docs://" + "guide/intro.md".
+// MIME types are detected from the extension, falling back to content
+// sniffing.
+func FSResources(fsys fs.FS, uriPrefix string) ResourceProvider {
+	return &fsResources{fsys: fsys, prefix: uriPrefix}
+}
+
+// ListResources implements ResourceProvider.
+func (p *fsResources) ListResources(ctx context.Context) ([]protocol.Resource, error) {
+	var resources []protocol.Resource
+
+	err := fs.WalkDir(p.fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		resources = append(resources, protocol.Resource{
+			URI:      p.prefix + name,
+			Name:     name,
+			MimeType: mime.TypeByExtension(path.Ext(name)),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing resources: %w", err)
+	}
+
+	return resources, nil
+}
+
+// ReadResource implements ResourceProvider.
+func (p *fsResources) ReadResource(ctx context.Context, uri string) (*protocol.ResourceReadResult, error) {
+	name, ok := strings.CutPrefix(uri, p.prefix)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownResource, uri)
+	}
+	if !fs.ValidPath(name) || name == "." {
+		return nil, fmt.Errorf("%w: invalid resource path in %s", ErrInvalidParams, uri)
+	}
+
+	data, err := fs.ReadFile(p.fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownResource, uri)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading resource %s: %w", uri, err)
+	}
+
+	return &protocol.ResourceReadResult{
+		Contents: []protocol.ResourceContent{fileContent(uri, name, data)},
+	}, nil
+}
+
+// ListResourceTemplates implements ResourceProvider.
+func (p *fsResources) ListResourceTemplates(ctx context.Context) ([]protocol.ResourceTemplate, error) {
+	return nil, nil
+}
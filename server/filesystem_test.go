@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFSResources(t *testing.T) {
+	fsys := fstest.MapFS{
+		"README.md":      {Data: []byte("# Docs\n")},
+		"guide/intro.md": {Data: []byte("hello\n")},
+		"img/logo.png":   {Data: []byte("\x89PNG\r\n\x1a\n")},
+	}
+	p := FSResources(fsys, "docs://")
+	ctx := context.Background()
+
+	resources, err := p.ListResources(ctx)
+	if err != nil {
+		t.Fatalf("ListResources: %v", err)
+	}
+	var uris []string
+	for _, r := range resources {
+		uris = append(uris, r.URI)
+	}
+	want := []string{"docs://README.md", "docs://guide/intro.md", "docs://img/logo.png"}
+	if len(uris) != len(want) {
+		t.Fatalf("uris = %v, want %v", uris, want)
+	}
+	for i := range want {
+		if uris[i] != want[i] {
+			t.Fatalf("uris = %v, want %v", uris, want)
+		}
+	}
+
+	result, err := p.ReadResource(ctx, "docs://guide/intro.md")
+	if err != nil {
+		t.Fatalf("ReadResource: %v", err)
+	}
+	if c := result.Contents[0]; c.Text != "hello\n" || c.MimeType != "text/markdown; charset=utf-8" {
+		t.Errorf("content = %+v", c)
+	}
+
+	logo, err := p.ReadResource(ctx, "docs://img/logo.png")
+	if err != nil {
+		t.Fatalf("ReadResource: %v", err)
+	}
+	if c := logo.Contents[0]; c.Blob == "" || c.MimeType != "image/png" {
+		t.Errorf("binary content = %+v", c)
+	}
+}
+
+func TestFSResourcesInvalidURIs(t *testing.T) {
+	p := FSResources(fstest.MapFS{"a.txt": {Data: []byte("a")}}, "docs://")
+	ctx := context.Background()
+
+	for uri, want := range map[string]error{
+		"other://a.txt":   ErrUnknownResource,
+		"docs://missing":  ErrUnknownResource,
+		"docs://../a.txt": ErrInvalidParams,
+		"docs:///a.txt":   ErrInvalidParams,
+		"docs://":         ErrInvalidParams,
+	} {
+		if _, err := p.ReadResource(ctx, uri); !errors.Is(err, want) {
+			t.Errorf("ReadResource(%q) err = %v, want %v", uri, err, want)
+		}
+	}
+}
+
+func TestFileSystemResourcesRefusesExcludedAndEscapingReads(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "root")
+	for name, content := range map[string]string{
+		"root/notes.md":               "notes",
+		"root/drafts/secret.md":       "draft",
+		"root/docs/secrets/key.txt":   "key",
+		"root/docs/public/readme.txt": "readme",
+		"outside.txt":                 "outside",
+	} {
+		name = filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(filepath.Join(dir, "outside.txt"), filepath.Join(root, "escape.txt")); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(root, "drafts", "secret.md"), filepath.Join(root, "alias.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	p := FileSystemResources(root, FSOptions{Exclude: []string{"drafts", "docs/secrets"}})
+	ctx := context.Background()
+
+	for _, name := range []string{"notes.md", "docs/public/readme.txt"} {
+		if _, err := p.ReadResource(ctx, fileURI(filepath.Join(root, filepath.FromSlash(name)))); err != nil {
+			t.Errorf("ReadResource(%s): %v", name, err)
+		}
+	}
+
+	for _, uri := range []string{
+		fileURI(filepath.Join(root, "drafts", "secret.md")),
+		fileURI(filepath.Join(root, "docs", "secrets", "key.txt")),
+		fileURI(root) + "/../outside.txt",
+		fileURI(filepath.Join(root, "escape.txt")),
+		fileURI(filepath.Join(root, "alias.md")),
+	} {
+		if _, err := p.ReadResource(ctx, uri); err == nil {
+			t.Errorf("ReadResource(%s) succeeded, want refusal", uri)
+		}
+	}
+}
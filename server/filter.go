@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+// filteredToolProvider exposes the subset of an inner provider's tools
+// permitted by allow and deny glob lists.
+type filteredToolProvider struct {
+	inner ToolProvider
+	allow []string
+	deny  []string
+}
+
+// notifyingFilteredToolProvider forwards list-change callbacks to an inner
+// provider that supports them.
+type notifyingFilteredToolProvider struct {
+	*filteredToolProvider
+	ListChangeNotifier
+}
+
+// FilteredToolProvider wraps inner so that only tools permitted by the allow
+// and deny lists are listed and callable. Entries are glob patterns in
+// path.Match syntax (e.g. "git_*"). An empty allow list permits every tool;
+// deny takes priority over allow. When inner is a ToolRegistry, calls to
+// excluded tools get the same result as unregistered ones, from its
+// SetNotFoundHandler or EnableSuggestions, and suggestions only name
+// permitted tools. Other providers' excluded tools get a plain
+// "unknown tool" error result.
+func FilteredToolProvider(inner ToolProvider, allow, deny []string) ToolProvider {
+	p := &filteredToolProvider{inner: inner, allow: allow, deny: deny}
+	if n, ok := inner.(ListChangeNotifier); ok {
+		return &notifyingFilteredToolProvider{p, n}
+	}
+	return p
+}
+
+// ListTools implements ToolProvider.
+func (p *filteredToolProvider) ListTools(ctx context.Context) ([]protocol.Tool, error) {
+	tools, err := p.inner.ListTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]protocol.Tool, 0, len(tools))
+	for _, t := range tools {
+		if p.permits(t.Name) {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered, nil
+}
+
+// CallTool implements ToolProvider.
+func (p *filteredToolProvider) CallTool(ctx context.Context, name string, args json.RawMessage) (*protocol.ToolCallResult, error) {
+	if reg, ok := p.inner.(*ToolRegistry); ok && (!p.permits(name) || !reg.Has(name)) {
+		return reg.notFoundResult(name, p.permits), nil
+	}
+	if !p.permits(name) {
+		return protocol.ErrorResult(fmt.Sprintf("unknown tool: %s", name)), nil
+	}
+	return p.inner.CallTool(ctx, name, args)
+}
+
+func (p *filteredToolProvider) permits(name string) bool {
+	if globMatchesAny(p.deny, name) {
+		return false
+	}
+	return len(p.allow) == 0 || globMatchesAny(p.allow, name)
+}
+
+func globMatchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -3,29 +3,59 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync/atomic"
 
 	"github.com/amarbel-llc/go-lib-mcp/jsonrpc"
+	"github.com/amarbel-llc/go-lib-mcp/output"
 	"github.com/amarbel-llc/go-lib-mcp/protocol"
 )
 
-// Handler handles MCP protocol method calls.
+// Handler handles MCP protocol method calls for a single connection.
+// It holds that connection's handshake state, so each connection needs its own.
 type Handler struct {
 	server      *Server
-	initialized bool
+	sess        *session
+	initialized atomic.Bool
+	client      atomic.Pointer[protocol.InitializeParams]
+	defaults    atomic.Pointer[output.Defaults]
+	unknown     atomic.Pointer[[]string]
 }
 
-// NewHandler creates a new handler for the given server.
+// NewHandler creates a new handler for the given server with fresh
+// per-connection state.
 func NewHandler(s *Server) *Handler {
 	return &Handler{server: s}
 }
 
 // Handle dispatches an incoming message to the appropriate handler method.
 func (h *Handler) Handle(ctx context.Context, msg *jsonrpc.Message) (*jsonrpc.Message, error) {
+	if !msg.IsRequest() && expectsResponse(msg.Method) {
+		return jsonrpc.NewErrorResponse(jsonrpc.ID{}, jsonrpc.InvalidRequest,
+			"missing id for request method "+msg.Method, nil)
+	}
+
 	switch msg.Method {
 	case protocol.MethodInitialize:
 		return h.handleInitialize(ctx, msg)
 	case protocol.MethodInitialized:
+		if h.sess != nil {
+			h.sess.ready.fire()
+		}
+		h.warnOnVersionMismatch()
+		h.warnOnUnknownCapabilities()
 		return nil, nil // Notification, no response
+	case protocol.MethodRootsListChanged:
+		h.handleRootsListChanged(ctx, msg)
+		return nil, nil
+	case protocol.MethodCancelled:
+		h.handleCancelled(msg)
+		h.dispatchNotification(ctx, msg)
+		return nil, nil
 	case protocol.MethodPing:
 		return h.handlePing(ctx, msg)
 	case protocol.MethodToolsList:
@@ -36,162 +66,524 @@ func (h *Handler) Handle(ctx context.Context, msg *jsonrpc.Message) (*jsonrpc.Me
 		return h.handleResourcesList(ctx, msg)
 	case protocol.MethodResourcesRead:
 		return h.handleResourcesRead(ctx, msg)
+	case protocol.MethodResourcesReadMany:
+		return h.handleResourcesReadMany(ctx, msg)
 	case protocol.MethodResourcesTemplates:
 		return h.handleResourcesTemplates(ctx, msg)
+	case protocol.MethodResourcesSubscribe, protocol.MethodResourcesUnsubscribe:
+		return h.handleResourcesSubscribe(ctx, msg)
 	case protocol.MethodPromptsList:
 		return h.handlePromptsList(ctx, msg)
 	case protocol.MethodPromptsGet:
 		return h.handlePromptsGet(ctx, msg)
 	default:
 		if msg.IsRequest() {
-			return jsonrpc.NewErrorResponse(*msg.ID, jsonrpc.MethodNotFound,
+			return jsonrpc.NewErrorResponse(msg.RequestID(), jsonrpc.MethodNotFound,
 				"method not found: "+msg.Method, nil)
 		}
+		h.dispatchNotification(ctx, msg)
 		return nil, nil
 	}
 }
 
+// errorCode maps a provider error to a JSON-RPC error code.
+func errorCode(err error) int {
+	switch {
+	case errors.Is(err, ErrInvalidParams), errors.Is(err, ErrUnknownTool),
+		errors.Is(err, ErrUnknownResource), errors.Is(err, ErrUnknownPrompt):
+		return jsonrpc.InvalidParams
+	}
+	return jsonrpc.InternalError
+}
+
+// expectsResponse reports whether method is one the server answers, so a
+// message calling it without an ID is malformed.
+func expectsResponse(method string) bool {
+	switch method {
+	case protocol.MethodInitialize, protocol.MethodPing,
+		protocol.MethodToolsList, protocol.MethodToolsCall,
+		protocol.MethodResourcesList, protocol.MethodResourcesRead,
+		protocol.MethodResourcesReadMany, protocol.MethodResourcesTemplates,
+		protocol.MethodResourcesSubscribe, protocol.MethodResourcesUnsubscribe,
+		protocol.MethodPromptsList, protocol.MethodPromptsGet:
+		return true
+	}
+	return false
+}
+
+// dispatchNotification passes a client notification to Options.NotificationHandler.
+func (h *Handler) dispatchNotification(ctx context.Context, msg *jsonrpc.Message) {
+	if fn := h.server.opts.NotificationHandler; fn != nil {
+		fn(ctx, msg.Method, msg.Params)
+	}
+}
+
+// handleCancelled cancels the context of the request the client gave up on.
+func (h *Handler) handleCancelled(msg *jsonrpc.Message) {
+	if h.sess == nil {
+		return
+	}
+	var params protocol.CancelledNotification
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		return
+	}
+	var id jsonrpc.ID
+	if err := json.Unmarshal(params.RequestID, &id); err != nil {
+		return
+	}
+	h.sess.inflight.cancel(id, params.Reason)
+}
+
 func (h *Handler) handleInitialize(ctx context.Context, msg *jsonrpc.Message) (*jsonrpc.Message, error) {
 	var params protocol.InitializeParams
 	if err := json.Unmarshal(msg.Params, &params); err != nil {
-		return jsonrpc.NewErrorResponse(*msg.ID, jsonrpc.InvalidParams, "invalid params", nil)
+		return jsonrpc.NewErrorResponse(msg.RequestID(), jsonrpc.InvalidParams, "invalid params", nil)
 	}
 
-	h.initialized = true
-
-	capabilities := protocol.ServerCapabilities{}
-	if h.server.opts.Tools != nil {
-		capabilities.Tools = &protocol.ToolsCapability{}
+	if params.ProtocolVersion != "" && params.ProtocolVersion < protocol.MinProtocolVersion {
+		return jsonrpc.NewErrorResponse(msg.RequestID(), jsonrpc.InvalidParams,
+			"unsupported protocol version: "+params.ProtocolVersion,
+			protocol.UnsupportedVersionData{
+				Requested: params.ProtocolVersion,
+				Minimum:   protocol.MinProtocolVersion,
+				Maximum:   protocol.ProtocolVersion,
+			})
 	}
-	if h.server.opts.Resources != nil {
-		capabilities.Resources = &protocol.ResourcesCapability{}
+
+	unknown := unknownCapabilities(msg.Params)
+	if len(unknown) > 0 && h.server.opts.StrictCapabilities {
+		return jsonrpc.NewErrorResponse(msg.RequestID(), jsonrpc.InvalidParams,
+			"unknown client capabilities: "+strings.Join(unknown, ", "),
+			map[string]any{"unknown": unknown})
 	}
-	if h.server.opts.Prompts != nil {
-		capabilities.Prompts = &protocol.PromptsCapability{}
+	h.unknown.Store(&unknown)
+
+	h.client.Store(&params)
+	if fn := h.server.opts.OutputDefaults; fn != nil {
+		defaults := fn(params)
+		h.defaults.Store(&defaults)
 	}
+	h.initialized.Store(true)
 
 	result := protocol.InitializeResult{
 		ProtocolVersion: protocol.ProtocolVersion,
-		Capabilities:    capabilities,
+		Capabilities:    h.clientCapabilities(),
 		ServerInfo: protocol.Implementation{
 			Name:    h.server.opts.ServerName,
 			Version: h.server.opts.ServerVersion,
 		},
 	}
 
-	return jsonrpc.NewResponse(*msg.ID, result)
+	return jsonrpc.NewResponse(msg.RequestID(), result)
+}
+
+// warnOnVersionMismatch tells the client when the negotiated protocol version
+// differs from the one it requested, if Options.WarnOnVersionMismatch is set.
+func (h *Handler) warnOnVersionMismatch() {
+	if !h.server.opts.WarnOnVersionMismatch {
+		return
+	}
+	client := h.client.Load()
+	if client == nil || client.Capabilities.Logging == nil || client.ProtocolVersion == protocol.ProtocolVersion {
+		return
+	}
+
+	h.notify(protocol.MethodLoggingMessage, protocol.LoggingMessageNotification{
+		Level:  protocol.LogLevelWarning,
+		Logger: h.server.opts.ServerName,
+		Data: fmt.Sprintf("client requested protocol version %s; using %s",
+			client.ProtocolVersion, protocol.ProtocolVersion),
+	})
+}
+
+func (h *Handler) warnOnUnknownCapabilities() {
+	if !h.server.opts.WarnOnUnknownCapabilities {
+		return
+	}
+	client, unknown := h.client.Load(), h.unknown.Load()
+	if client == nil || client.Capabilities.Logging == nil || unknown == nil || len(*unknown) == 0 {
+		return
+	}
+
+	h.notify(protocol.MethodLoggingMessage, protocol.LoggingMessageNotification{
+		Level:  protocol.LogLevelWarning,
+		Logger: h.server.opts.ServerName,
+		Data:   "client advertised unsupported capabilities: " + strings.Join(*unknown, ", "),
+	})
+}
+
+// knownClientCapabilities holds the JSON names of protocol.ClientCapabilities.
+var knownClientCapabilities = func() map[string]bool {
+	known := make(map[string]bool)
+	t := reflect.TypeOf(protocol.ClientCapabilities{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		known[name] = true
+	}
+	return known
+}()
+
+// unknownCapabilities returns the sorted top-level capability keys in raw
+// initialize params that protocol.ClientCapabilities does not define.
+func unknownCapabilities(params json.RawMessage) []string {
+	var raw struct {
+		Capabilities map[string]json.RawMessage `json:"capabilities"`
+	}
+	if err := json.Unmarshal(params, &raw); err != nil {
+		return nil
+	}
+
+	var unknown []string
+	for key := range raw.Capabilities {
+		if !knownClientCapabilities[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	slices.Sort(unknown)
+	return unknown
+}
+
+// clientCapabilities returns the capabilities advertised to this connection's
+// client, applying Options.CapabilityFilter.
+func (h *Handler) clientCapabilities() protocol.ServerCapabilities {
+	capabilities := h.capabilities()
+	if filter := h.server.opts.CapabilityFilter; filter != nil {
+		capabilities = filter(h.clientParams(), capabilities)
+	}
+	return capabilities
+}
+
+// capabilities returns the configured capability override, or derives the
+// capabilities from the configured providers.
+func (h *Handler) capabilities() protocol.ServerCapabilities {
+	if h.server.opts.Capabilities != nil {
+		return *h.server.opts.Capabilities
+	}
+
+	capabilities := protocol.ServerCapabilities{}
+	if h.server.opts.Tools != nil {
+		capabilities.Tools = &protocol.ToolsCapability{
+			ListChanged: notifiesListChanges(h.server.opts.Tools),
+		}
+	}
+	if h.server.opts.Resources != nil {
+		capabilities.Resources = &protocol.ResourcesCapability{
+			ListChanged: notifiesListChanges(h.server.opts.Resources),
+		}
+		if sub, ok := h.server.opts.Resources.(ResourceSubscriber); ok && sub.SubscriptionMode() != "" {
+			capabilities.Resources.Subscribe = true
+			capabilities.Experimental = map[string]any{
+				protocol.ExperimentalSubscriptionMode: protocol.SubscriptionModeCapability{
+					Mode: string(sub.SubscriptionMode()),
+				},
+			}
+		}
+	}
+	if h.server.opts.Prompts != nil {
+		capabilities.Prompts = &protocol.PromptsCapability{
+			ListChanged: notifiesListChanges(h.server.opts.Prompts),
+		}
+	}
+	if h.readManyEnabled() {
+		if capabilities.Experimental == nil {
+			capabilities.Experimental = map[string]any{}
+		}
+		capabilities.Experimental[protocol.ExperimentalReadMany] = struct{}{}
+	}
+	if h.resourceListDiffEnabled() {
+		if capabilities.Experimental == nil {
+			capabilities.Experimental = map[string]any{}
+		}
+		capabilities.Experimental[protocol.ExperimentalResourceListDiff] = struct{}{}
+	}
+
+	return capabilities
+}
+
+// notify sends a notification on this handler's connection.
+func (h *Handler) notify(method string, params any) error {
+	msg, err := jsonrpc.NewNotification(method, params)
+	if err != nil {
+		return err
+	}
+	if h.sess != nil {
+		return h.sess.transport.Write(msg)
+	}
+	return h.server.session().transport.Write(msg)
+}
+
+func notifiesListChanges(provider any) bool {
+	_, ok := provider.(ListChangeNotifier)
+	return ok
 }
 
 func (h *Handler) handlePing(ctx context.Context, msg *jsonrpc.Message) (*jsonrpc.Message, error) {
-	return jsonrpc.NewResponse(*msg.ID, protocol.PingResult{})
+	var params protocol.PingParams
+	if len(msg.Params) > 0 {
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return jsonrpc.NewErrorResponse(msg.RequestID(), jsonrpc.InvalidParams, "invalid params", nil)
+		}
+	}
+
+	meta := params.Meta
+	if info := h.server.opts.BuildInfo; info != nil {
+		meta = withBuildInfo(meta, info)
+	}
+	return jsonrpc.NewResponse(msg.RequestID(), protocol.PingResult{Meta: meta})
+}
+
+// withBuildInfo adds info to a ping's _meta object under "buildInfo". Meta
+// that is not an object is returned unchanged.
+func withBuildInfo(meta json.RawMessage, info *protocol.BuildInfo) json.RawMessage {
+	fields := map[string]any{}
+	if len(meta) > 0 {
+		if err := json.Unmarshal(meta, &fields); err != nil {
+			return meta
+		}
+	}
+	fields["buildInfo"] = info
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return meta
+	}
+	return data
 }
 
 func (h *Handler) handleToolsList(ctx context.Context, msg *jsonrpc.Message) (*jsonrpc.Message, error) {
 	if h.server.opts.Tools == nil {
-		return jsonrpc.NewErrorResponse(*msg.ID, jsonrpc.InternalError, "tools not supported", nil)
+		return jsonrpc.NewErrorResponse(msg.RequestID(), jsonrpc.InternalError, "tools not supported", nil)
 	}
 
-	tools, err := h.server.opts.Tools.ListTools(ctx)
+	tools, err := h.listTools(ctx)
 	if err != nil {
-		return jsonrpc.NewErrorResponse(*msg.ID, jsonrpc.InternalError, err.Error(), nil)
+		return jsonrpc.NewErrorResponse(msg.RequestID(), errorCode(err), err.Error(), nil)
 	}
 
 	result := protocol.ToolsListResult{Tools: tools}
-	return jsonrpc.NewResponse(*msg.ID, result)
+	return jsonrpc.NewResponse(msg.RequestID(), result)
 }
 
 func (h *Handler) handleToolsCall(ctx context.Context, msg *jsonrpc.Message) (*jsonrpc.Message, error) {
 	if h.server.opts.Tools == nil {
-		return jsonrpc.NewErrorResponse(*msg.ID, jsonrpc.InternalError, "tools not supported", nil)
+		return jsonrpc.NewErrorResponse(msg.RequestID(), jsonrpc.InternalError, "tools not supported", nil)
 	}
 
 	var params protocol.ToolCallParams
 	if err := json.Unmarshal(msg.Params, &params); err != nil {
-		return jsonrpc.NewErrorResponse(*msg.ID, jsonrpc.InvalidParams, "invalid params", nil)
+		return jsonrpc.NewErrorResponse(msg.RequestID(), jsonrpc.InvalidParams, "invalid params", nil)
 	}
 
+	if h.server.opts.ReadOnly || h.server.opts.ToolFilter != nil {
+		tool, listed, err := h.lookupTool(ctx, params.Name)
+		if err != nil {
+			return jsonrpc.NewErrorResponse(msg.RequestID(), errorCode(err), err.Error(), nil)
+		}
+		// Tools hidden by the filter are treated as unknown.
+		if h.server.opts.ToolFilter != nil && !listed {
+			return jsonrpc.NewResponse(msg.RequestID(), protocol.ErrorResult("unknown tool: "+params.Name))
+		}
+		// Read-only mode fails closed: a tool the provider does not list
+		// carries no readOnlyHint, so it is refused like any other.
+		if h.server.opts.ReadOnly && !tool.IsReadOnly() {
+			return jsonrpc.NewResponse(msg.RequestID(), protocol.ErrorResultWithCode("permission_denied",
+				"tool "+params.Name+" is not read-only and the server is in read-only mode"))
+		}
+	}
+
+	ctx = withProgress(ctx, params.Meta, h.notify)
+
 	result, err := h.server.opts.Tools.CallTool(ctx, params.Name, params.Arguments)
+	var toolErr *ToolError
+	if errors.As(err, &toolErr) {
+		return jsonrpc.NewResponse(msg.RequestID(), protocol.ErrorResultWithCode(toolErr.Code, toolErr.Error()))
+	}
 	if err != nil {
-		return jsonrpc.NewErrorResponse(*msg.ID, jsonrpc.InternalError, err.Error(), nil)
+		return jsonrpc.NewErrorResponse(msg.RequestID(), errorCode(err), err.Error(), nil)
 	}
 
-	return jsonrpc.NewResponse(*msg.ID, result)
+	// Guard against handlers returning (nil, nil) or nil content, which would
+	// otherwise produce a result the client cannot parse. The result is
+	// copied first: handlers may return a shared value.
+	var normalized protocol.ToolCallResult
+	if result != nil {
+		normalized = *result
+	}
+	if normalized.Content == nil {
+		normalized.Content = []protocol.ContentBlock{}
+	}
+	normalized.Content = limitContentBlocks(normalized.Content, h.server.opts.MaxContentBlocks)
+
+	return jsonrpc.NewResponse(msg.RequestID(), normalized)
+}
+
+// limitContentBlocks keeps the first max blocks and appends a text block
+// noting how many were omitted. A max of zero or less leaves blocks as is.
+func limitContentBlocks(blocks []protocol.ContentBlock, max int) []protocol.ContentBlock {
+	if max <= 0 || len(blocks) <= max {
+		return blocks
+	}
+
+	omitted := len(blocks) - max
+	limited := make([]protocol.ContentBlock, max, max+1)
+	copy(limited, blocks)
+	return append(limited, protocol.TextContent(fmt.Sprintf("%d more blocks omitted", omitted)))
+}
+
+// listTools lists the provider's tools as seen by this connection's client,
+// applying Options.ToolFilter.
+func (h *Handler) listTools(ctx context.Context) ([]protocol.Tool, error) {
+	tools, err := h.server.opts.Tools.ListTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if filter := h.server.opts.ToolFilter; filter != nil {
+		tools = filter(h.clientParams(), tools)
+	}
+	return tools, nil
+}
+
+// lookupTool finds the named tool among those listed to this connection's
+// client, reporting whether it was listed.
+func (h *Handler) lookupTool(ctx context.Context, name string) (protocol.Tool, bool, error) {
+	tools, err := h.listTools(ctx)
+	if err != nil {
+		return protocol.Tool{}, false, err
+	}
+	for _, t := range tools {
+		if t.Name == name {
+			return t, true, nil
+		}
+	}
+	return protocol.Tool{}, false, nil
+}
+
+// clientParams returns the client's initialize params, or the zero value
+// before initialize.
+func (h *Handler) clientParams() protocol.InitializeParams {
+	if client := h.client.Load(); client != nil {
+		return *client
+	}
+	return protocol.InitializeParams{}
 }
 
 func (h *Handler) handleResourcesList(ctx context.Context, msg *jsonrpc.Message) (*jsonrpc.Message, error) {
 	if h.server.opts.Resources == nil {
-		return jsonrpc.NewErrorResponse(*msg.ID, jsonrpc.InternalError, "resources not supported", nil)
+		return jsonrpc.NewErrorResponse(msg.RequestID(), jsonrpc.InternalError, "resources not supported", nil)
 	}
 
 	resources, err := h.server.opts.Resources.ListResources(ctx)
 	if err != nil {
-		return jsonrpc.NewErrorResponse(*msg.ID, jsonrpc.InternalError, err.Error(), nil)
+		return jsonrpc.NewErrorResponse(msg.RequestID(), errorCode(err), err.Error(), nil)
 	}
 
 	result := protocol.ResourcesListResult{Resources: resources}
-	return jsonrpc.NewResponse(*msg.ID, result)
+	return jsonrpc.NewResponse(msg.RequestID(), result)
 }
 
 func (h *Handler) handleResourcesRead(ctx context.Context, msg *jsonrpc.Message) (*jsonrpc.Message, error) {
 	if h.server.opts.Resources == nil {
-		return jsonrpc.NewErrorResponse(*msg.ID, jsonrpc.InternalError, "resources not supported", nil)
+		return jsonrpc.NewErrorResponse(msg.RequestID(), jsonrpc.InternalError, "resources not supported", nil)
 	}
 
 	var params protocol.ResourceReadParams
 	if err := json.Unmarshal(msg.Params, &params); err != nil {
-		return jsonrpc.NewErrorResponse(*msg.ID, jsonrpc.InvalidParams, "invalid params", nil)
+		return jsonrpc.NewErrorResponse(msg.RequestID(), jsonrpc.InvalidParams, "invalid params", nil)
 	}
 
 	result, err := h.server.opts.Resources.ReadResource(ctx, params.URI)
 	if err != nil {
-		return jsonrpc.NewErrorResponse(*msg.ID, jsonrpc.InternalError, err.Error(), nil)
+		return jsonrpc.NewErrorResponse(msg.RequestID(), errorCode(err), err.Error(), nil)
+	}
+
+	return jsonrpc.NewResponse(msg.RequestID(), result)
+}
+
+func (h *Handler) resourceListDiffEnabled() bool {
+	if !h.server.opts.EnableResourceListDiff {
+		return false
+	}
+	_, differ := h.server.opts.Resources.(ResourceDiffer)
+	return differ && notifiesListChanges(h.server.opts.Resources)
+}
+
+func (h *Handler) readManyEnabled() bool {
+	return h.server.opts.EnableReadMany && h.server.opts.Resources != nil
+}
+
+func (h *Handler) handleResourcesReadMany(ctx context.Context, msg *jsonrpc.Message) (*jsonrpc.Message, error) {
+	if !h.readManyEnabled() {
+		return jsonrpc.NewErrorResponse(msg.RequestID(), jsonrpc.MethodNotFound,
+			"method not found: "+msg.Method, nil)
+	}
+
+	var params protocol.ResourceReadManyParams
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		return jsonrpc.NewErrorResponse(msg.RequestID(), jsonrpc.InvalidParams, "invalid params", nil)
+	}
+
+	result := protocol.ResourceReadManyResult{
+		Results: make([]protocol.ResourceReadManyEntry, 0, len(params.URIs)),
+	}
+
+	for _, uri := range params.URIs {
+		entry := protocol.ResourceReadManyEntry{URI: uri}
+
+		read, err := h.server.opts.Resources.ReadResource(ctx, uri)
+		if err != nil {
+			entry.Error = err.Error()
+		} else if read != nil {
+			entry.Contents = read.Contents
+		}
+
+		result.Results = append(result.Results, entry)
 	}
 
-	return jsonrpc.NewResponse(*msg.ID, result)
+	return jsonrpc.NewResponse(msg.RequestID(), result)
 }
 
 func (h *Handler) handleResourcesTemplates(ctx context.Context, msg *jsonrpc.Message) (*jsonrpc.Message, error) {
 	if h.server.opts.Resources == nil {
-		return jsonrpc.NewErrorResponse(*msg.ID, jsonrpc.InternalError, "resources not supported", nil)
+		return jsonrpc.NewErrorResponse(msg.RequestID(), jsonrpc.InternalError, "resources not supported", nil)
 	}
 
 	templates, err := h.server.opts.Resources.ListResourceTemplates(ctx)
 	if err != nil {
-		return jsonrpc.NewErrorResponse(*msg.ID, jsonrpc.InternalError, err.Error(), nil)
+		return jsonrpc.NewErrorResponse(msg.RequestID(), errorCode(err), err.Error(), nil)
 	}
 
 	result := protocol.ResourceTemplatesListResult{ResourceTemplates: templates}
-	return jsonrpc.NewResponse(*msg.ID, result)
+	return jsonrpc.NewResponse(msg.RequestID(), result)
 }
 
 func (h *Handler) handlePromptsList(ctx context.Context, msg *jsonrpc.Message) (*jsonrpc.Message, error) {
 	if h.server.opts.Prompts == nil {
-		return jsonrpc.NewErrorResponse(*msg.ID, jsonrpc.InternalError, "prompts not supported", nil)
+		return jsonrpc.NewErrorResponse(msg.RequestID(), jsonrpc.InternalError, "prompts not supported", nil)
 	}
 
 	prompts, err := h.server.opts.Prompts.ListPrompts(ctx)
 	if err != nil {
-		return jsonrpc.NewErrorResponse(*msg.ID, jsonrpc.InternalError, err.Error(), nil)
+		return jsonrpc.NewErrorResponse(msg.RequestID(), errorCode(err), err.Error(), nil)
 	}
 
 	result := protocol.PromptsListResult{Prompts: prompts}
-	return jsonrpc.NewResponse(*msg.ID, result)
+	return jsonrpc.NewResponse(msg.RequestID(), result)
 }
 
 func (h *Handler) handlePromptsGet(ctx context.Context, msg *jsonrpc.Message) (*jsonrpc.Message, error) {
 	if h.server.opts.Prompts == nil {
-		return jsonrpc.NewErrorResponse(*msg.ID, jsonrpc.InternalError, "prompts not supported", nil)
+		return jsonrpc.NewErrorResponse(msg.RequestID(), jsonrpc.InternalError, "prompts not supported", nil)
 	}
 
 	var params protocol.PromptGetParams
 	if err := json.Unmarshal(msg.Params, &params); err != nil {
-		return jsonrpc.NewErrorResponse(*msg.ID, jsonrpc.InvalidParams, "invalid params", nil)
+		return jsonrpc.NewErrorResponse(msg.RequestID(), jsonrpc.InvalidParams, "invalid params", nil)
 	}
 
 	result, err := h.server.opts.Prompts.GetPrompt(ctx, params.Name, params.Arguments)
 	if err != nil {
-		return jsonrpc.NewErrorResponse(*msg.ID, jsonrpc.InternalError, err.Error(), nil)
+		return jsonrpc.NewErrorResponse(msg.RequestID(), errorCode(err), err.Error(), nil)
 	}
 
-	return jsonrpc.NewResponse(*msg.ID, result)
+	return jsonrpc.NewResponse(msg.RequestID(), result)
 }
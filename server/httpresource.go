@@ -0,0 +1,42 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+// HTTPHandlerResource returns a ResourceReader that serves req to h in-process
+// on every read and returns the recorded body with the recorded Content-Type.
+// No network listener is involved. The request is cloned with the read's
+// context; a nil req means GET /. Non-2xx responses are reported as errors.
+//
+// The uri is only used to describe the resource in errors; contents are
+// returned with the URI being read.
+func HTTPHandlerResource(uri string, h http.Handler, req *http.Request) ResourceReader {
+	return func(ctx context.Context, readURI string) (*protocol.ResourceReadResult, error) {
+		var r *http.Request
+		if req == nil {
+			r = httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+		} else {
+			r = req.Clone(ctx)
+		}
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+
+		if rec.Code < 200 || rec.Code > 299 {
+			return nil, fmt.Errorf("reading resource %s: handler returned %d %s",
+				uri, rec.Code, http.StatusText(rec.Code))
+		}
+
+		return &protocol.ResourceReadResult{
+			Contents: []protocol.ResourceContent{
+				bytesContent(readURI, rec.Header().Get("Content-Type"), rec.Body.Bytes()),
+			},
+		}, nil
+	}
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/amarbel-llc/go-lib-mcp/jsonrpc"
+)
+
+// cancellation is the cause of a request context cancelled by the client.
+type cancellation struct {
+	reason string
+}
+
+func (c *cancellation) Error() string {
+	if c.reason == "" {
+		return "request cancelled by client"
+	}
+	return "request cancelled by client: " + c.reason
+}
+
+// CancellationReasonFromContext returns the reason the client gave in
+// notifications/cancelled when it cancelled the current request. It returns
+// "" if the request was not cancelled by the client or no reason was given.
+func CancellationReasonFromContext(ctx context.Context) string {
+	var c *cancellation
+	if errors.As(context.Cause(ctx), &c) {
+		return c.reason
+	}
+	return ""
+}
+
+// inflight tracks the cancel functions of requests being handled, keyed by
+// the JSON encoding of the request ID so that 1 and "1" stay distinct.
+type inflight struct {
+	mu      sync.Mutex
+	cancels map[string]*inflightRequest
+}
+
+type inflightRequest struct {
+	cancel context.CancelCauseFunc
+}
+
+// track derives a cancelable context for the request with the given ID.
+// The returned function must be called when the request completes.
+func (f *inflight) track(ctx context.Context, requestID jsonrpc.ID) (context.Context, func()) {
+	id := inflightKey(requestID)
+	ctx, cancel := context.WithCancelCause(ctx)
+	req := &inflightRequest{cancel: cancel}
+
+	f.mu.Lock()
+	if f.cancels == nil {
+		f.cancels = make(map[string]*inflightRequest)
+	}
+	f.cancels[id] = req
+	f.mu.Unlock()
+
+	return ctx, func() {
+		f.mu.Lock()
+		// A client reusing an ID may have replaced this entry.
+		if f.cancels[id] == req {
+			delete(f.cancels, id)
+		}
+		f.mu.Unlock()
+		cancel(nil)
+	}
+}
+
+// cancel cancels the request with the given ID, if it is still running.
+func (f *inflight) cancel(requestID jsonrpc.ID, reason string) {
+	f.mu.Lock()
+	req, ok := f.cancels[inflightKey(requestID)]
+	f.mu.Unlock()
+
+	if ok {
+		req.cancel(&cancellation{reason: reason})
+	}
+}
+
+// inflightKey encodes a request ID with its type, so numeric and string IDs
+// with the same text do not collide.
+func inflightKey(id jsonrpc.ID) string {
+	data, _ := id.MarshalJSON()
+	return string(data)
+}
+
+func (f *inflight) cancelAll() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, req := range f.cancels {
+		req.cancel(nil)
+	}
+}
+
+// CancelAll cancels the contexts of all requests currently being handled on
+// the current connection. The server keeps running and new requests are
+// handled normally; handlers that honor their context return early.
+func (s *Server) CancelAll() {
+	s.session().inflight.cancelAll()
+}
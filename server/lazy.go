@@ -0,0 +1,46 @@
+package server
+
+import (
+	"context"
+	"sync"
+
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+// ResourceFactory builds a resource's reader on first use.
+type ResourceFactory func(ctx context.Context) (ResourceReader, error)
+
+// RegisterLazy adds a static resource whose reader is built by factory the
+// first time the resource is read and reused afterwards. The metadata is
+// listed immediately, so it should be cheap to produce; the expensive setup
+// is deferred to factory. If factory fails the error is returned to the
+// reader and factory is tried again on the next read.
+func (r *ResourceRegistry) RegisterLazy(resource protocol.Resource, factory ResourceFactory) {
+	lazy := &lazyReader{factory: factory}
+	r.RegisterResource(resource, lazy.read)
+}
+
+// lazyReader builds its reader on first use.
+type lazyReader struct {
+	factory ResourceFactory
+
+	mu     sync.Mutex
+	reader ResourceReader
+}
+
+func (l *lazyReader) read(ctx context.Context, uri string) (*protocol.ResourceReadResult, error) {
+	l.mu.Lock()
+	reader := l.reader
+	if reader == nil {
+		var err error
+		reader, err = l.factory(ctx)
+		if err != nil {
+			l.mu.Unlock()
+			return nil, err
+		}
+		l.reader = reader
+	}
+	l.mu.Unlock()
+
+	return reader(ctx, uri)
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/amarbel-llc/go-lib-mcp/output"
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+// lineRange is a 1-based, inclusive line range; an End of zero means the
+// last line.
+type lineRange struct {
+	Start, End int
+}
+
+var lineFragment = regexp.MustCompile(`^L(\d+)(?:-L?(\d+))?$`)
+
+// parseLineFragment splits a URI ending in a GitHub-style line anchor such as
+// #L100-L200 or #L42 into the base URI and the range.
+func parseLineFragment(uri string) (string, lineRange, bool) {
+	i := strings.LastIndexByte(uri, '#')
+	if i < 0 {
+		return "", lineRange{}, false
+	}
+
+	m := lineFragment.FindStringSubmatch(uri[i+1:])
+	if m == nil {
+		return "", lineRange{}, false
+	}
+
+	start, err := strconv.Atoi(m[1])
+	if err != nil {
+		return "", lineRange{}, false
+	}
+	end := start
+	if m[2] != "" {
+		if end, err = strconv.Atoi(m[2]); err != nil {
+			return "", lineRange{}, false
+		}
+	}
+
+	return uri[:i], lineRange{Start: start, End: end}, true
+}
+
+// sliceResult narrows each text content of result to the given lines,
+// recording the selected range and total line count in its _meta. Binary
+// content is returned unchanged. Ranges are clamped to the lines that exist.
+func sliceResult(result *protocol.ResourceReadResult, uri string, lines lineRange) *protocol.ResourceReadResult {
+	sliced := *result
+	sliced.Contents = slices.Clone(result.Contents)
+
+	for i, c := range sliced.Contents {
+		if c.Blob != "" {
+			continue
+		}
+		s := output.SliceLines(c.Text, lines.Start, lines.End)
+		c.URI = uri
+		c.Text = s.Content
+		c.Meta = &protocol.ResourceContentMeta{
+			StartLine:  s.Start,
+			EndLine:    s.End,
+			TotalLines: s.TotalLines,
+		}
+		sliced.Contents[i] = c
+	}
+
+	return &sliced
+}
@@ -0,0 +1,147 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/amarbel-llc/go-lib-mcp/executor"
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+// Manifest is a JSON document declaring subprocess-backed tools.
+//
+//	{
+//	  "tools": [
+//	    {
+//	      "name": "word_count",
+//	      "description": "Counts words in the input",
+//	      "inputSchema": {"type": "object", "properties": {"text": {"type": "string"}}},
+//	      "command": "nixpkgs#coreutils",
+//	      "args": ["--some-flag"]
+//	    }
+//	  ]
+//	}
+type Manifest struct {
+	Tools []ManifestTool `json:"tools"`
+}
+
+// ManifestTool declares a single tool backed by a subprocess.
+type ManifestTool struct {
+	// Name is the tool name.
+	Name string `json:"name"`
+
+	// Description explains what the tool does (optional).
+	Description string `json:"description,omitempty"`
+
+	// InputSchema is the tool's JSON Schema. Defaults to {"type": "object"}.
+	InputSchema json.RawMessage `json:"inputSchema,omitempty"`
+
+	// Command is the executor spec resolved with Executor.Build
+	// (e.g. a Nix flake reference for the nix executor).
+	Command string `json:"command"`
+
+	// Args are passed to the command on every invocation (optional).
+	Args []string `json:"args,omitempty"`
+}
+
+// ParseManifest parses and validates a tool manifest.
+func ParseManifest(data []byte) (*Manifest, error) {
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing manifest: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for i, t := range m.Tools {
+		if t.Name == "" {
+			return nil, fmt.Errorf("manifest tool %d: name is required", i)
+		}
+		if t.Command == "" {
+			return nil, fmt.Errorf("manifest tool %s: command is required", t.Name)
+		}
+		if seen[t.Name] {
+			return nil, fmt.Errorf("manifest tool %s: duplicate name", t.Name)
+		}
+		seen[t.Name] = true
+
+		if len(t.InputSchema) == 0 {
+			m.Tools[i].InputSchema = json.RawMessage(`{"type":"object"}`)
+		}
+	}
+
+	return &m, nil
+}
+
+// RegisterFromManifest parses a tool manifest and registers each tool on reg.
+// Invoking a tool builds its command with exec, runs it with the tool's JSON
+// arguments on stdin, and returns stdout as text. A non-zero exit produces an
+// error result containing stderr. Nothing is registered if the manifest is
+// invalid; registration stops at the first tool whose name is already taken.
+func RegisterFromManifest(reg *ToolRegistry, exec executor.Executor, manifest []byte) error {
+	m, err := ParseManifest(manifest)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range m.Tools {
+		if err := reg.Register(t.Name, t.Description, t.InputSchema, manifestHandler(exec, t)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func manifestHandler(exec executor.Executor, t ManifestTool) ToolHandler {
+	return func(ctx context.Context, args json.RawMessage) (*protocol.ToolCallResult, error) {
+		path, err := exec.Build(ctx, t.Command)
+		if err != nil {
+			return nil, fmt.Errorf("building %s: %w", t.Command, err)
+		}
+
+		proc, err := exec.Execute(ctx, path, t.Args)
+		if err != nil {
+			return nil, fmt.Errorf("starting %s: %w", t.Command, err)
+		}
+
+		if len(args) == 0 {
+			args = json.RawMessage("{}")
+		}
+
+		go func() {
+			proc.Stdin.Write(args)
+			proc.Stdin.Close()
+		}()
+
+		var stderr bytes.Buffer
+		stderrDone := make(chan struct{})
+		go func() {
+			defer close(stderrDone)
+			if proc.Stderr != nil {
+				io.Copy(&stderr, proc.Stderr)
+			}
+		}()
+
+		stdout, readErr := io.ReadAll(proc.Stdout)
+		<-stderrDone
+
+		if err := proc.Wait(); err != nil {
+			msg := strings.TrimSpace(stderr.String())
+			if msg == "" {
+				msg = err.Error()
+			}
+			return protocol.ErrorResult(fmt.Sprintf("%s failed: %s", t.Name, msg)), nil
+		}
+		if readErr != nil {
+			return nil, fmt.Errorf("reading output of %s: %w", t.Command, readErr)
+		}
+
+		return &protocol.ToolCallResult{
+			Content: []protocol.ContentBlock{protocol.TextContent(string(stdout))},
+		}, nil
+	}
+}
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/amarbel-llc/go-lib-mcp/executor"
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+// ManifestWatchOptions configures WatchManifest.
+type ManifestWatchOptions struct {
+	// Interval is how often the manifest file is checked for changes.
+	// Defaults to one second.
+	Interval time.Duration
+
+	// Logger receives reloads and reload failures. Defaults to slog.Default().
+	Logger *slog.Logger
+}
+
+// WatchManifest registers the tools of the manifest at path on reg, like
+// RegisterFromManifest, then watches the file until ctx is done. Each time
+// its contents change the manifest's tools are replaced in one step, so
+// clients get a single notifications/tools/list_changed; tools registered
+// on reg by other means are left alone.
+//
+// The initial load's errors are returned. Later, a manifest that cannot be
+// read or parsed, or whose tools clash with other tools on reg, is logged
+// once and the previous tool set kept; the reload is retried on every
+// check until it succeeds, so a clash resolves itself once the other tool
+// is unregistered.
+//
+// The file is polled, which also picks up editors that replace it by
+// renaming, rather than watched with OS file notifications.
+func WatchManifest(ctx context.Context, reg *ToolRegistry, exec executor.Executor, path string, opts ManifestWatchOptions) error {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading manifest: %w", err)
+	}
+	names, err := reloadManifest(reg, exec, nil, data)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		// failed holds the contents of the last failed reload, so each
+		// version of the file is only reported once while it is retried.
+		var failed []byte
+
+		ticker := time.NewTicker(opts.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			current, err := os.ReadFile(path)
+			if err != nil {
+				opts.Logger.WarnContext(ctx, "reading manifest failed, keeping previous tools",
+					slog.String("path", path), slog.Any("error", err))
+				continue
+			}
+			if bytes.Equal(current, data) {
+				continue
+			}
+
+			reloaded, err := reloadManifest(reg, exec, names, current)
+			if err != nil {
+				if !bytes.Equal(current, failed) {
+					opts.Logger.WarnContext(ctx, "reloading manifest failed, keeping previous tools",
+						slog.String("path", path), slog.Any("error", err))
+				}
+				failed = current
+				continue
+			}
+			data, failed, names = current, nil, reloaded
+			opts.Logger.InfoContext(ctx, "reloaded manifest",
+				slog.String("path", path), slog.Int("tools", len(names)))
+		}
+	}()
+
+	return nil
+}
+
+// reloadManifest replaces the tools named in previous with those declared by
+// manifest, returning the new tools' names.
+func reloadManifest(reg *ToolRegistry, exec executor.Executor, previous []string, manifest []byte) ([]string, error) {
+	m, err := ParseManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	tools := make([]protocol.Tool, len(m.Tools))
+	handlers := make([]ToolHandler, len(m.Tools))
+	names := make([]string, len(m.Tools))
+	for i, t := range m.Tools {
+		tools[i] = protocol.Tool{Name: t.Name, Description: t.Description, InputSchema: t.InputSchema}
+		handlers[i] = manifestHandler(exec, t)
+		names[i] = t.Name
+	}
+
+	if err := reg.replace(previous, tools, handlers); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// replace unregisters the tools named in previous and registers tools with
+// their handlers in one step, notifying once. Nothing changes if two of the
+// new tools share a name or one clashes with a tool not being replaced.
+func (r *ToolRegistry) replace(previous []string, tools []protocol.Tool, handlers []ToolHandler) error {
+	seen := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		if seen[tool.Name] {
+			return fmt.Errorf("%w: %s", ErrDuplicateTool, tool.Name)
+		}
+		seen[tool.Name] = true
+	}
+
+	r.mu.Lock()
+	for _, tool := range tools {
+		if _, ok := r.handlers[tool.Name]; ok && !slices.Contains(previous, tool.Name) {
+			r.mu.Unlock()
+			return fmt.Errorf("%w: %s", ErrDuplicateTool, tool.Name)
+		}
+	}
+
+	for _, name := range previous {
+		delete(r.handlers, name)
+	}
+	r.tools = slices.DeleteFunc(r.tools, func(t protocol.Tool) bool {
+		return slices.Contains(previous, t.Name)
+	})
+	for i, tool := range tools {
+		r.tools = append(r.tools, tool)
+		r.handlers[tool.Name] = handlers[i]
+	}
+	onChange := r.onChange
+	r.mu.Unlock()
+
+	for _, fn := range onChange {
+		fn()
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+// syncBuffer is a bytes.Buffer safe for a logger and a test to share.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestWatchManifestReloads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tools.json")
+	writeManifest := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitFor := func(what string, cond func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for !cond() {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %s", what)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	reg := NewToolRegistry()
+	reg.Register("static", "", json.RawMessage(`{"type":"object"}`), noopTool)
+	var mu sync.Mutex
+	changes := 0
+	reg.OnListChanged(func() {
+		mu.Lock()
+		changes++
+		mu.Unlock()
+	})
+
+	var logs syncBuffer
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	writeManifest(`{"tools":[{"name":"a","command":"x"}]}`)
+	err := WatchManifest(ctx, reg, nil, path, ManifestWatchOptions{
+		Interval: 5 * time.Millisecond,
+		Logger:   slog.New(slog.NewTextHandler(&logs, nil)),
+	})
+	if err != nil {
+		t.Fatalf("WatchManifest: %v", err)
+	}
+	if !reg.Has("a") {
+		t.Fatal("initial manifest tool not registered")
+	}
+
+	mu.Lock()
+	changes = 0
+	mu.Unlock()
+	writeManifest(`{"tools":[{"name":"b","command":"x"},{"name":"c","command":"x"}]}`)
+	waitFor("reload", func() bool { return reg.Has("b") && reg.Has("c") && !reg.Has("a") })
+	if !reg.Has("static") {
+		t.Error("reload removed a tool not from the manifest")
+	}
+	mu.Lock()
+	if changes != 1 {
+		t.Errorf("list changes = %d, want 1", changes)
+	}
+	mu.Unlock()
+
+	writeManifest(`{"tools":[`)
+	waitFor("reload failure", func() bool { return strings.Contains(logs.String(), "parsing manifest") })
+	writeManifest(`{"tools":[{"name":"static","command":"x"}]}`)
+	waitFor("clash", func() bool { return strings.Contains(logs.String(), "duplicate tool") })
+	if !reg.Has("b") || !reg.Has("c") {
+		t.Error("failed reload dropped the previous tools")
+	}
+	if n := strings.Count(logs.String(), "duplicate tool"); n != 1 {
+		t.Errorf("clash logged %d times, want once", n)
+	}
+
+	// The unchanged manifest is retried once the clash goes away.
+	reg.Unregister("static")
+	waitFor("retried reload", func() bool { return reg.Has("static") && !reg.Has("b") })
+}
+
+func TestToolRegistryReplaceRejectsDuplicateNames(t *testing.T) {
+	reg := NewToolRegistry()
+	tools := []protocol.Tool{{Name: "a"}, {Name: "a"}}
+	err := reg.replace(nil, tools, []ToolHandler{noopTool, noopTool})
+	if !errors.Is(err, ErrDuplicateTool) {
+		t.Fatalf("err = %v, want ErrDuplicateTool", err)
+	}
+	if reg.Has("a") {
+		t.Error("failed replace registered a tool")
+	}
+}
+
+func TestWatchManifestInitialError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tools.json")
+	os.WriteFile(path, []byte(`{"tools":[{"name":"a"}]}`), 0o644)
+
+	err := WatchManifest(context.Background(), NewToolRegistry(), nil, path, ManifestWatchOptions{})
+	if err == nil || !strings.Contains(err.Error(), "command is required") {
+		t.Errorf("err = %v, want invalid manifest error", err)
+	}
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/amarbel-llc/go-lib-mcp/jsonrpc"
+)
+
+// HandlerFunc handles an incoming request or notification. It returns the
+// response to send, or nil for notifications.
+type HandlerFunc func(ctx context.Context, msg *jsonrpc.Message) (*jsonrpc.Message, error)
+
+// Middleware wraps a HandlerFunc with cross-cutting behavior such as logging.
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// chain wraps h with middleware so that the first middleware is outermost.
+func chain(h HandlerFunc, middleware []Middleware) HandlerFunc {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		h = middleware[i](h)
+	}
+	return h
+}
+
+// LoggingOption configures LoggingMiddleware.
+type LoggingOption func(*loggingConfig)
+
+type loggingConfig struct {
+	params bool
+	redact func(json.RawMessage) json.RawMessage
+}
+
+// WithLoggedParams adds each message's params to the debug-level entry log,
+// passed through redact first if it is not nil. Pointers given to a Redactor
+// are relative to the params, so tool arguments are under /arguments, e.g.
+// Redactor("/arguments/password").
+func WithLoggedParams(redact func(json.RawMessage) json.RawMessage) LoggingOption {
+	return func(c *loggingConfig) {
+		c.params = true
+		c.redact = redact
+	}
+}
+
+// LoggingMiddleware logs each message's method and ID at debug level on entry
+// and its duration on exit: at info level on success, and at error level when
+// the handler fails or answers with a JSON-RPC error. Notifications are logged
+// without an ID.
+func LoggingMiddleware(logger *slog.Logger, opts ...LoggingOption) Middleware {
+	var cfg loggingConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, msg *jsonrpc.Message) (*jsonrpc.Message, error) {
+			attrs := []any{slog.String("method", msg.Method)}
+			if msg.IsRequest() {
+				attrs = append(attrs, slog.String("id", msg.ID.String()))
+			}
+
+			if cfg.params && len(msg.Params) > 0 {
+				params := msg.Params
+				if cfg.redact != nil {
+					params = cfg.redact(params)
+				}
+				logger.DebugContext(ctx, "handling message", append(attrs, slog.String("params", string(params)))...)
+			} else {
+				logger.DebugContext(ctx, "handling message", attrs...)
+			}
+
+			start := time.Now()
+			resp, err := next(ctx, msg)
+			attrs = append(attrs, slog.Duration("duration", time.Since(start)))
+
+			switch {
+			case err != nil:
+				logger.ErrorContext(ctx, "message failed", append(attrs, slog.Any("error", err))...)
+			case resp != nil && resp.Error != nil:
+				logger.ErrorContext(ctx, "message failed", append(attrs,
+					slog.Int("code", resp.Error.Code),
+					slog.String("error", resp.Error.Message))...)
+			default:
+				logger.InfoContext(ctx, "message handled", attrs...)
+			}
+
+			return resp, err
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+// multiToolProvider presents several tool providers as one.
+type multiToolProvider struct {
+	providers []ToolProvider
+
+	mu         sync.Mutex
+	owners     map[string]ToolProvider // from the last ListTools; nil if stale
+	generation int
+	onChange   []func()
+}
+
+// notifyingMultiToolProvider reports list changes of any inner provider
+// that supports them.
+type notifyingMultiToolProvider struct {
+	*multiToolProvider
+}
+
+// MultiToolProvider combines several tool providers into one. ListTools
+// concatenates their tools in provider order and CallTool routes each call to
+// the provider that lists the tool. Tool names must be unique across
+// providers: a collision is reported here, and by ListTools if one appears
+// later through dynamic registration.
+//
+// CallTool routes with the ownership seen by the last ListTools, refreshed
+// when an inner ListChangeNotifier reports a change or a name is not found,
+// so calls do not list every provider. MultiToolProvider takes over the
+// list-change callbacks of such inner providers.
+func MultiToolProvider(providers ...ToolProvider) (ToolProvider, error) {
+	p := &multiToolProvider{providers: providers}
+
+	notifies := false
+	for _, inner := range providers {
+		if n, ok := inner.(ListChangeNotifier); ok {
+			n.OnListChanged(p.listChanged)
+			notifies = true
+		}
+	}
+
+	if _, err := p.ListTools(context.Background()); err != nil {
+		return nil, err
+	}
+
+	if notifies {
+		return &notifyingMultiToolProvider{p}, nil
+	}
+	return p, nil
+}
+
+// listChanged drops the cached ownership and passes the change on.
+func (p *multiToolProvider) listChanged() {
+	p.mu.Lock()
+	p.owners = nil
+	p.generation++
+	onChange := p.onChange
+	p.mu.Unlock()
+
+	for _, fn := range onChange {
+		fn()
+	}
+}
+
+// ListTools implements ToolProvider.
+func (p *multiToolProvider) ListTools(ctx context.Context) ([]protocol.Tool, error) {
+	p.mu.Lock()
+	generation := p.generation
+	p.mu.Unlock()
+
+	var all []protocol.Tool
+	owners := make(map[string]int)
+	byName := make(map[string]ToolProvider)
+
+	for i, inner := range p.providers {
+		tools, err := inner.ListTools(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, t := range tools {
+			if j, ok := owners[t.Name]; ok {
+				return nil, fmt.Errorf("%w: %s is provided by both provider %d and provider %d", ErrDuplicateTool, t.Name, j, i)
+			}
+			owners[t.Name] = i
+			byName[t.Name] = inner
+			all = append(all, t)
+		}
+	}
+
+	p.mu.Lock()
+	// A change reported while listing may not be reflected in byName.
+	if p.generation == generation {
+		p.owners = byName
+	}
+	p.mu.Unlock()
+
+	return all, nil
+}
+
+// CallTool implements ToolProvider.
+func (p *multiToolProvider) CallTool(ctx context.Context, name string, args json.RawMessage) (*protocol.ToolCallResult, error) {
+	inner, ok := p.owner(name)
+	if !ok {
+		// The cache may be stale, e.g. for providers that do not notify.
+		if _, err := p.ListTools(ctx); err != nil {
+			return nil, err
+		}
+		inner, ok = p.owner(name)
+	}
+	if !ok {
+		return protocol.ErrorResult(fmt.Sprintf("unknown tool: %s", name)), nil
+	}
+	return inner.CallTool(ctx, name, args)
+}
+
+// owner returns the provider of the named tool from the cached ownership.
+func (p *multiToolProvider) owner(name string) (ToolProvider, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	inner, ok := p.owners[name]
+	return inner, ok
+}
+
+// OnListChanged implements ListChangeNotifier.
+func (p *notifyingMultiToolProvider) OnListChanged(fn func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onChange = append(p.onChange, fn)
+}
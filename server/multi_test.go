@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+// countingTools counts ListTools calls on the tools of a registry, without
+// exposing its list-change notifications.
+type countingTools struct {
+	reg   *ToolRegistry
+	lists atomic.Int32
+}
+
+func (c *countingTools) ListTools(ctx context.Context) ([]protocol.Tool, error) {
+	c.lists.Add(1)
+	return c.reg.ListTools(ctx)
+}
+
+func (c *countingTools) CallTool(ctx context.Context, name string, args json.RawMessage) (*protocol.ToolCallResult, error) {
+	return c.reg.CallTool(ctx, name, args)
+}
+
+func TestMultiToolProviderCachesOwnership(t *testing.T) {
+	static := &countingTools{reg: NewToolRegistry()}
+	static.reg.Register("a", "", nil, noopTool)
+	dynamic := NewToolRegistry()
+
+	p, err := MultiToolProvider(static, dynamic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var changes atomic.Int32
+	p.(ListChangeNotifier).OnListChanged(func() { changes.Add(1) })
+	ctx := context.Background()
+
+	listed := static.lists.Load()
+	for range 3 {
+		if result, err := p.CallTool(ctx, "a", nil); err != nil || result.IsError {
+			t.Fatalf("CallTool(a) = %+v, %v", result, err)
+		}
+	}
+	if n := static.lists.Load() - listed; n != 0 {
+		t.Errorf("CallTool listed tools %d times, want 0", n)
+	}
+
+	dynamic.Register("b", "", nil, noopTool)
+	if changes.Load() != 1 {
+		t.Errorf("list changes = %d, want 1", changes.Load())
+	}
+	if result, err := p.CallTool(ctx, "b", nil); err != nil || result.IsError {
+		t.Fatalf("CallTool(b) after registration = %+v, %v", result, err)
+	}
+
+	static.reg.Register("c", "", nil, noopTool)
+	if result, err := p.CallTool(ctx, "c", nil); err != nil || result.IsError {
+		t.Fatalf("CallTool(c) from a provider that does not notify = %+v, %v", result, err)
+	}
+
+	if result, _ := p.CallTool(ctx, "missing", nil); !result.IsError {
+		t.Error("CallTool(missing) succeeded")
+	}
+}
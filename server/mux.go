@@ -0,0 +1,295 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+// MuxSeparator joins a Mux namespace to the names of its tools, prompts,
+// and resources, e.g. "git_status".
+const MuxSeparator = "_"
+
+// Mux hosts several logical servers behind one Server, and so one
+// transport. Each is registered under a namespace with Options holding its
+// providers; every other field configures the whole Server and is rejected
+// by Handle.
+//
+// Tool and prompt names are prefixed with the namespace and MuxSeparator,
+// and calls are routed back by that prefix. Resource URIs are left
+// unchanged so clients can still open them; their display names are
+// prefixed, and reads go to the first namespace that knows the URI. Two
+// namespaces listing the same URI is an error when resources are listed.
+//
+// The Server handles initialize once, for all namespaces. Build its Options
+// with Mux.Options after registering every namespace: it advertises tools,
+// resources, or prompts if any namespace provides them, so the client sees
+// the union of the namespaces' capabilities. List changes in any namespace
+// are reported as changes to the merged list.
+type Mux struct {
+	mu      sync.RWMutex
+	entries []muxEntry
+	byName  map[string]int
+
+	onTools, onResources, onPrompts []func()
+}
+
+type muxEntry struct {
+	namespace string
+	tools     ToolProvider
+	resources ResourceProvider
+	prompts   PromptProvider
+}
+
+// NewMux creates an empty Mux.
+func NewMux() *Mux {
+	return &Mux{byName: make(map[string]int)}
+}
+
+// Handle registers the providers in opts under namespace, which must be
+// non-empty, unique, and free of MuxSeparator. Options fields other than
+// Tools, Resources, and Prompts cannot differ per namespace; setting any
+// of them is an error, and they belong on the Options passed to New.
+func (m *Mux) Handle(namespace string, opts Options) error {
+	if namespace == "" || strings.Contains(namespace, MuxSeparator) {
+		return fmt.Errorf("invalid mux namespace %q", namespace)
+	}
+	if fields := serverWideOptions(opts); len(fields) > 0 {
+		return fmt.Errorf("mux namespace %q: options %s apply to the whole server, set them on the Server's Options instead",
+			namespace, strings.Join(fields, ", "))
+	}
+
+	m.mu.Lock()
+	if _, ok := m.byName[namespace]; ok {
+		m.mu.Unlock()
+		return fmt.Errorf("mux namespace %q already registered", namespace)
+	}
+	m.byName[namespace] = len(m.entries)
+	m.entries = append(m.entries, muxEntry{
+		namespace: namespace,
+		tools:     opts.Tools,
+		resources: opts.Resources,
+		prompts:   opts.Prompts,
+	})
+	m.mu.Unlock()
+
+	forward := func(provider any, fns *[]func()) {
+		if n, ok := provider.(ListChangeNotifier); ok {
+			n.OnListChanged(func() {
+				m.mu.RLock()
+				onChange := *fns
+				m.mu.RUnlock()
+				for _, fn := range onChange {
+					fn()
+				}
+			})
+		}
+	}
+	if opts.Tools != nil {
+		forward(opts.Tools, &m.onTools)
+	}
+	if opts.Resources != nil {
+		forward(opts.Resources, &m.onResources)
+	}
+	if opts.Prompts != nil {
+		forward(opts.Prompts, &m.onPrompts)
+	}
+	return nil
+}
+
+// Options returns base with its providers replaced by the Mux's merged
+// providers, for passing to New.
+func (m *Mux) Options(base Options) Options {
+	base.Tools, base.Resources, base.Prompts = nil, nil, nil
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, e := range m.entries {
+		if e.tools != nil {
+			base.Tools = muxTools{m}
+		}
+		if e.resources != nil {
+			base.Resources = muxResources{m}
+		}
+		if e.prompts != nil {
+			base.Prompts = muxPrompts{m}
+		}
+	}
+	return base
+}
+
+// route splits a prefixed name into its namespace's entry and the
+// unprefixed name.
+func (m *Mux) route(name string) (muxEntry, string, bool) {
+	namespace, rest, ok := strings.Cut(name, MuxSeparator)
+	if !ok {
+		return muxEntry{}, "", false
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	i, ok := m.byName[namespace]
+	if !ok {
+		return muxEntry{}, "", false
+	}
+	return m.entries[i], rest, true
+}
+
+// snapshot returns the registered namespaces.
+func (m *Mux) snapshot() []muxEntry {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]muxEntry(nil), m.entries...)
+}
+
+func (m *Mux) addOnChange(fns *[]func(), fn func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	*fns = append(*fns, fn)
+}
+
+// serverWideOptions returns the names of the fields set in opts other than
+// its providers.
+func serverWideOptions(opts Options) []string {
+	opts.Tools, opts.Resources, opts.Prompts = nil, nil, nil
+
+	var fields []string
+	v := reflect.ValueOf(opts)
+	for i := range v.NumField() {
+		if !v.Field(i).IsZero() {
+			fields = append(fields, v.Type().Field(i).Name)
+		}
+	}
+	return fields
+}
+
+func prefixed(namespace, name string) string {
+	return namespace + MuxSeparator + name
+}
+
+// muxTools is the Mux's merged ToolProvider.
+type muxTools struct{ m *Mux }
+
+func (p muxTools) ListTools(ctx context.Context) ([]protocol.Tool, error) {
+	var all []protocol.Tool
+	for _, e := range p.m.snapshot() {
+		if e.tools == nil {
+			continue
+		}
+		tools, err := e.tools.ListTools(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.namespace, err)
+		}
+		for _, tool := range tools {
+			tool.Name = prefixed(e.namespace, tool.Name)
+			all = append(all, tool)
+		}
+	}
+	return all, nil
+}
+
+func (p muxTools) CallTool(ctx context.Context, name string, args json.RawMessage) (*protocol.ToolCallResult, error) {
+	e, rest, ok := p.m.route(name)
+	if !ok || e.tools == nil {
+		return protocol.ErrorResult("unknown tool: " + name), nil
+	}
+	return e.tools.CallTool(ctx, rest, args)
+}
+
+func (p muxTools) OnListChanged(fn func()) { p.m.addOnChange(&p.m.onTools, fn) }
+
+// muxResources is the Mux's merged ResourceProvider.
+type muxResources struct{ m *Mux }
+
+func (p muxResources) ListResources(ctx context.Context) ([]protocol.Resource, error) {
+	var all []protocol.Resource
+	owners := make(map[string]string)
+	for _, e := range p.m.snapshot() {
+		if e.resources == nil {
+			continue
+		}
+		resources, err := e.resources.ListResources(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.namespace, err)
+		}
+		for _, res := range resources {
+			if owner, ok := owners[res.URI]; ok && owner != e.namespace {
+				return nil, fmt.Errorf("resource %s is provided by both namespaces %s and %s", res.URI, owner, e.namespace)
+			}
+			owners[res.URI] = e.namespace
+			res.Name = prefixed(e.namespace, res.Name)
+			all = append(all, res)
+		}
+	}
+	return all, nil
+}
+
+func (p muxResources) ReadResource(ctx context.Context, uri string) (*protocol.ResourceReadResult, error) {
+	for _, e := range p.m.snapshot() {
+		if e.resources == nil {
+			continue
+		}
+		result, err := e.resources.ReadResource(ctx, uri)
+		if errors.Is(err, ErrUnknownResource) {
+			continue
+		}
+		return result, err
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownResource, uri)
+}
+
+func (p muxResources) ListResourceTemplates(ctx context.Context) ([]protocol.ResourceTemplate, error) {
+	var all []protocol.ResourceTemplate
+	for _, e := range p.m.snapshot() {
+		if e.resources == nil {
+			continue
+		}
+		templates, err := e.resources.ListResourceTemplates(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.namespace, err)
+		}
+		for _, tmpl := range templates {
+			tmpl.Name = prefixed(e.namespace, tmpl.Name)
+			all = append(all, tmpl)
+		}
+	}
+	return all, nil
+}
+
+func (p muxResources) OnListChanged(fn func()) { p.m.addOnChange(&p.m.onResources, fn) }
+
+// muxPrompts is the Mux's merged PromptProvider.
+type muxPrompts struct{ m *Mux }
+
+func (p muxPrompts) ListPrompts(ctx context.Context) ([]protocol.Prompt, error) {
+	var all []protocol.Prompt
+	for _, e := range p.m.snapshot() {
+		if e.prompts == nil {
+			continue
+		}
+		prompts, err := e.prompts.ListPrompts(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.namespace, err)
+		}
+		for _, prompt := range prompts {
+			prompt.Name = prefixed(e.namespace, prompt.Name)
+			all = append(all, prompt)
+		}
+	}
+	return all, nil
+}
+
+func (p muxPrompts) GetPrompt(ctx context.Context, name string, args map[string]string) (*protocol.PromptGetResult, error) {
+	e, rest, ok := p.m.route(name)
+	if !ok || e.prompts == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownPrompt, name)
+	}
+	return e.prompts.GetPrompt(ctx, rest, args)
+}
+
+func (p muxPrompts) OnListChanged(fn func()) { p.m.addOnChange(&p.m.onPrompts, fn) }
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+func TestMuxRoutesByNamespace(t *testing.T) {
+	echo := func(reply string) ToolHandler {
+		return func(ctx context.Context, args json.RawMessage) (*protocol.ToolCallResult, error) {
+			return &protocol.ToolCallResult{Content: []protocol.ContentBlock{protocol.TextContent(reply)}}, nil
+		}
+	}
+
+	gitTools := NewToolRegistry()
+	gitTools.Register("status", "", nil, echo("git"))
+	fsTools := NewToolRegistry()
+	fsTools.Register("status", "", nil, echo("fs"))
+	fsResources := NewResourceRegistry()
+	fsResources.RegisterResource(protocol.Resource{URI: "file:///a", Name: "a"}, func(ctx context.Context, uri string) (*protocol.ResourceReadResult, error) {
+		return &protocol.ResourceReadResult{Contents: []protocol.ResourceContent{{URI: uri, Text: "a"}}}, nil
+	})
+
+	mux := NewMux()
+	if err := mux.Handle("git", Options{Tools: gitTools}); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+	if err := mux.Handle("fs", Options{Tools: fsTools, Resources: fsResources}); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+	if err := mux.Handle("git", Options{}); err == nil {
+		t.Fatal("duplicate namespace accepted")
+	}
+	if err := mux.Handle("bad_name", Options{}); err == nil {
+		t.Fatal("namespace containing the separator accepted")
+	}
+
+	opts := mux.Options(Options{ServerName: "mux"})
+	if opts.Prompts != nil {
+		t.Fatal("prompts provider set although no namespace has prompts")
+	}
+	ctx := context.Background()
+
+	tools, err := opts.Tools.ListTools(ctx)
+	if err != nil {
+		t.Fatalf("ListTools: %v", err)
+	}
+	if len(tools) != 2 || tools[0].Name != "git_status" || tools[1].Name != "fs_status" {
+		t.Fatalf("tools = %+v, want git_status and fs_status", tools)
+	}
+
+	result, err := opts.Tools.CallTool(ctx, "fs_status", nil)
+	if err != nil || result.Content[0].Text != "fs" {
+		t.Fatalf("CallTool(fs_status) = %+v, %v; want fs", result, err)
+	}
+	if result, err := opts.Tools.CallTool(ctx, "svn_status", nil); err != nil || !result.IsError {
+		t.Fatalf("CallTool(svn_status) = %+v, %v; want an error result", result, err)
+	}
+
+	resources, _ := opts.Resources.ListResources(ctx)
+	if len(resources) != 1 || resources[0].URI != "file:///a" || resources[0].Name != "fs_a" {
+		t.Fatalf("resources = %+v, want file:///a named fs_a", resources)
+	}
+	if _, err := opts.Resources.ReadResource(ctx, "file:///a"); err != nil {
+		t.Fatalf("ReadResource: %v", err)
+	}
+
+	tr := newFakeTransport()
+	s, err := New(tr, opts)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	initialize(t, s)
+	gitTools.Register("log", "", nil, echo("log"))
+	if n := tr.count(protocol.MethodToolsListChanged); n != 1 {
+		t.Fatalf("tools list_changed = %d, want 1", n)
+	}
+}
+
+func TestMuxRejectsServerWideOptions(t *testing.T) {
+	mux := NewMux()
+	err := mux.Handle("git", Options{Tools: NewToolRegistry(), ReadOnly: true, ServerName: "git"})
+	if err == nil || !strings.Contains(err.Error(), "ServerName, ReadOnly") {
+		t.Fatalf("err = %v, want ServerName and ReadOnly rejected", err)
+	}
+	if err := mux.Handle("git", Options{Tools: NewToolRegistry()}); err != nil {
+		t.Fatalf("Handle after rejection: %v", err)
+	}
+}
+
+func TestMuxDetectsResourceCollisions(t *testing.T) {
+	read := func(ctx context.Context, uri string) (*protocol.ResourceReadResult, error) {
+		return &protocol.ResourceReadResult{}, nil
+	}
+	a := NewResourceRegistry()
+	a.RegisterResource(protocol.Resource{URI: "file:///shared", Name: "shared"}, read)
+	b := NewResourceRegistry()
+	b.RegisterResource(protocol.Resource{URI: "file:///shared", Name: "shared"}, read)
+
+	mux := NewMux()
+	mux.Handle("a", Options{Resources: a})
+	mux.Handle("b", Options{Resources: b})
+
+	_, err := mux.Options(Options{}).Resources.ListResources(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "file:///shared") {
+		t.Fatalf("err = %v, want a collision on file:///shared", err)
+	}
+}
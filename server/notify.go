@@ -0,0 +1,128 @@
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/amarbel-llc/go-lib-mcp/jsonrpc"
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+// Notify sends a notification to the client.
+func (s *Server) Notify(method string, params any) error {
+	msg, err := jsonrpc.NewNotification(method, params)
+	if err != nil {
+		return err
+	}
+	return s.session().transport.Write(msg)
+}
+
+// watchListChanges installs a list-changed callback on providers that
+// implement ListChangeNotifier.
+func (s *Server) watchListChanges() {
+	watch := func(provider any, method string) {
+		n, ok := provider.(ListChangeNotifier)
+		if !ok {
+			return
+		}
+		d := newDebouncer(s.opts.ListChangedDebounce, func() {
+			if method == protocol.MethodResourcesListChanged {
+				s.notifyResourcesChanged()
+				return
+			}
+			s.notifyListChanged(method)
+		})
+		s.debouncers = append(s.debouncers, d)
+		n.OnListChanged(d.trigger)
+	}
+
+	if s.opts.Tools != nil {
+		watch(s.opts.Tools, protocol.MethodToolsListChanged)
+	}
+	if s.opts.Resources != nil {
+		watch(s.opts.Resources, protocol.MethodResourcesListChanged)
+	}
+	if s.opts.Prompts != nil {
+		watch(s.opts.Prompts, protocol.MethodPromptsListChanged)
+	}
+}
+
+// notifyListChanged sends a list_changed notification once the client has
+// initialized. Changes made before then are already reflected in the first list.
+func (s *Server) notifyListChanged(method string) {
+	if !s.session().handler.initialized.Load() {
+		return
+	}
+	s.Notify(method, nil)
+}
+
+// notifyResourcesChanged sends the resource list diff to clients that
+// understand it, and list_changed to the rest.
+func (s *Server) notifyResourcesChanged() {
+	h := s.session().handler
+	if !h.resourceListDiffEnabled() {
+		s.notifyListChanged(protocol.MethodResourcesListChanged)
+		return
+	}
+
+	// Always advance the baseline, so a later diff only covers later changes.
+	diff := s.opts.Resources.(ResourceDiffer).ResourceDiff()
+
+	client := h.client.Load()
+	if !h.initialized.Load() || client == nil {
+		return
+	}
+	if _, ok := client.Capabilities.Experimental[protocol.ExperimentalResourceListDiff]; !ok {
+		s.Notify(protocol.MethodResourcesListChanged, nil)
+		return
+	}
+	if !diff.IsEmpty() {
+		s.Notify(protocol.MethodResourcesListDiff, diff)
+	}
+}
+
+// debouncer coalesces calls to trigger that occur within window into a single
+// call to fn at the end of the window. The window starts at the first trigger,
+// so a steady stream of changes still produces a notification every window.
+type debouncer struct {
+	window  time.Duration
+	fn      func()
+	mu      sync.Mutex
+	pending *time.Timer
+}
+
+func newDebouncer(window time.Duration, fn func()) *debouncer {
+	return &debouncer{window: window, fn: fn}
+}
+
+func (d *debouncer) trigger() {
+	if d.window <= 0 {
+		d.fn()
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.pending != nil {
+		return
+	}
+
+	d.pending = time.AfterFunc(d.window, func() {
+		d.mu.Lock()
+		d.pending = nil
+		d.mu.Unlock()
+		d.fn()
+	})
+}
+
+// stop drops a pending call to fn, if any.
+func (d *debouncer) stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.pending != nil {
+		d.pending.Stop()
+		d.pending = nil
+	}
+}
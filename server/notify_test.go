@@ -0,0 +1,251 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amarbel-llc/go-lib-mcp/jsonrpc"
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+// fakeTransport feeds queued messages to the server and records everything
+// the server writes.
+type fakeTransport struct {
+	in chan *jsonrpc.Message
+
+	mu      sync.Mutex
+	written []*jsonrpc.Message
+}
+
+func newFakeTransport() *fakeTransport {
+	return &fakeTransport{in: make(chan *jsonrpc.Message, 16)}
+}
+
+func (t *fakeTransport) Read() (*jsonrpc.Message, error) {
+	msg, ok := <-t.in
+	if !ok {
+		return nil, io.EOF
+	}
+	return msg, nil
+}
+
+func (t *fakeTransport) Write(msg *jsonrpc.Message) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.written = append(t.written, msg)
+	return nil
+}
+
+func (t *fakeTransport) Close() error { return nil }
+
+func (t *fakeTransport) count(method string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	n := 0
+	for _, msg := range t.written {
+		if msg.Method == method {
+			n++
+		}
+	}
+	return n
+}
+
+func initialize(t *testing.T, s *Server) {
+	t.Helper()
+	req, _ := jsonrpc.NewRequest(jsonrpc.NewNumberID(1), protocol.MethodInitialize, protocol.InitializeParams{
+		ProtocolVersion: protocol.ProtocolVersion,
+	})
+	if _, err := s.session().handler.Handle(context.Background(), req); err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+	initialized, _ := jsonrpc.NewNotification(protocol.MethodInitialized, nil)
+	if _, err := s.session().handler.Handle(context.Background(), initialized); err != nil {
+		t.Fatalf("initialized: %v", err)
+	}
+}
+
+func noopTool(ctx context.Context, args json.RawMessage) (*protocol.ToolCallResult, error) {
+	return &protocol.ToolCallResult{}, nil
+}
+
+func TestListChangedDebounceBatchesChanges(t *testing.T) {
+	tr := newFakeTransport()
+	tools := NewToolRegistry()
+
+	s, err := New(tr, Options{
+		ServerName:          "test",
+		Tools:               tools,
+		ListChangedDebounce: 50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	initialize(t, s)
+
+	for i := 0; i < 50; i++ {
+		tools.Register(fmt.Sprintf("tool%d", i), "", nil, noopTool)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+
+	if n := tr.count(protocol.MethodToolsListChanged); n != 1 {
+		t.Fatalf("list_changed notifications = %d, want 1", n)
+	}
+}
+
+func TestListChangedDebounceStopsWithSession(t *testing.T) {
+	tr := newFakeTransport()
+	tools := NewToolRegistry()
+
+	s, err := New(tr, Options{
+		ServerName:          "test",
+		Tools:               tools,
+		ListChangedDebounce: 50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	initialize(t, s)
+
+	tools.Register("late", "", nil, noopTool)
+	close(tr.in)
+	if err := s.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if n := tr.count(protocol.MethodToolsListChanged); n != 0 {
+		t.Fatalf("list_changed notifications after the session ended = %d, want 0", n)
+	}
+}
+
+func TestRegistryCallsEveryListChangedCallback(t *testing.T) {
+	tools := NewToolRegistry()
+	var first, second int
+	tools.OnListChanged(func() { first++ })
+	tools.OnListChanged(func() { second++ })
+
+	tools.Register("a", "", nil, noopTool)
+	if first != 1 || second != 1 {
+		t.Fatalf("callbacks called %d and %d times, want 1 each", first, second)
+	}
+}
+
+func TestListChangedWithoutDebounce(t *testing.T) {
+	tr := newFakeTransport()
+	tools := NewToolRegistry()
+
+	s, err := New(tr, Options{ServerName: "test", Tools: tools})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	tools.Register("before_init", "", nil, noopTool)
+	if n := tr.count(protocol.MethodToolsListChanged); n != 0 {
+		t.Fatalf("expected no notification before initialize, got %d", n)
+	}
+
+	initialize(t, s)
+	tools.Register("a", "", nil, noopTool)
+	tools.Register("b", "", nil, noopTool)
+
+	if n := tr.count(protocol.MethodToolsListChanged); n != 2 {
+		t.Fatalf("list_changed notifications = %d, want 2", n)
+	}
+}
+
+func TestResourceListDiff(t *testing.T) {
+	tr := newFakeTransport()
+	resources := NewResourceRegistry()
+	resources.RegisterResource(protocol.Resource{URI: "file:///a", Name: "a"}, nil)
+
+	s, err := New(tr, Options{
+		ServerName:             "test",
+		Resources:              resources,
+		EnableResourceListDiff: true,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	req, _ := jsonrpc.NewRequest(jsonrpc.NewNumberID(1), protocol.MethodInitialize, protocol.InitializeParams{
+		ProtocolVersion: protocol.ProtocolVersion,
+		Capabilities: protocol.ClientCapabilities{
+			Experimental: map[string]any{protocol.ExperimentalResourceListDiff: struct{}{}},
+		},
+	})
+	resp, err := s.session().handler.Handle(context.Background(), req)
+	if err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+	var init protocol.InitializeResult
+	if err := json.Unmarshal(resp.Result, &init); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if _, ok := init.Capabilities.Experimental[protocol.ExperimentalResourceListDiff]; !ok {
+		t.Fatalf("capability not advertised: %v", init.Capabilities.Experimental)
+	}
+
+	if _, err := resources.ListResources(context.Background()); err != nil {
+		t.Fatalf("ListResources: %v", err)
+	}
+	resources.RegisterResource(protocol.Resource{URI: "file:///b", Name: "b"}, nil)
+	resources.Unregister("file:///a")
+
+	if n := tr.count(protocol.MethodResourcesListChanged); n != 0 {
+		t.Fatalf("list_changed notifications = %d, want 0", n)
+	}
+
+	var diffs []protocol.ResourceListDiff
+	tr.mu.Lock()
+	for _, msg := range tr.written {
+		if msg.Method != protocol.MethodResourcesListDiff {
+			continue
+		}
+		var diff protocol.ResourceListDiff
+		if err := json.Unmarshal(msg.Params, &diff); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		diffs = append(diffs, diff)
+	}
+	tr.mu.Unlock()
+
+	if len(diffs) != 2 {
+		t.Fatalf("diff notifications = %d, want 2", len(diffs))
+	}
+	if len(diffs[0].Added) != 1 || diffs[0].Added[0].URI != "file:///b" || len(diffs[0].Removed) != 0 {
+		t.Errorf("first diff = %+v, want file:///b added", diffs[0])
+	}
+	if len(diffs[1].Added) != 0 || len(diffs[1].Removed) != 1 || diffs[1].Removed[0] != "file:///a" {
+		t.Errorf("second diff = %+v, want file:///a removed", diffs[1])
+	}
+}
+
+func TestResourceListDiffFallsBackToListChanged(t *testing.T) {
+	tr := newFakeTransport()
+	resources := NewResourceRegistry()
+
+	s, err := New(tr, Options{
+		ServerName:             "test",
+		Resources:              resources,
+		EnableResourceListDiff: true,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	initialize(t, s)
+	resources.RegisterResource(protocol.Resource{URI: "file:///a", Name: "a"}, nil)
+
+	if n := tr.count(protocol.MethodResourcesListChanged); n != 1 {
+		t.Fatalf("list_changed notifications = %d, want 1", n)
+	}
+	if n := tr.count(protocol.MethodResourcesListDiff); n != 0 {
+		t.Fatalf("list_diff notifications = %d, want 0", n)
+	}
+}
@@ -1,5 +1,15 @@
 package server
 
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/amarbel-llc/go-lib-mcp/jsonrpc"
+	"github.com/amarbel-llc/go-lib-mcp/output"
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
 // Options configures an MCP server.
 type Options struct {
 	// ServerName is the name of this MCP server.
@@ -19,4 +29,120 @@ type Options struct {
 	// Prompts is the prompt provider (optional).
 	// If nil, the server will not advertise prompt capabilities.
 	Prompts PromptProvider
+
+	// EnableReadMany enables the experimental resources/read_many method and
+	// advertises it under the experimental capabilities. Requires Resources.
+	EnableReadMany bool
+
+	// EnableResourceListDiff advertises the experimental
+	// notifications/resources/list_diff extension. Clients that also
+	// advertise it receive the added and removed resources instead of
+	// notifications/resources/list_changed; other clients are unaffected.
+	// Requires a Resources provider implementing ResourceDiffer and
+	// ListChangeNotifier, such as ResourceRegistry.
+	EnableResourceListDiff bool
+
+	// ListChangedDebounce coalesces list changes that occur within this window
+	// into a single notifications/*/list_changed notification.
+	// Zero sends a notification for every change.
+	ListChangedDebounce time.Duration
+
+	// MaxPendingRequests caps the number of server-to-client requests awaiting
+	// a response. Further requests fail with ErrTooManyPending.
+	// Zero means unlimited.
+	MaxPendingRequests int
+
+	// OutboundRequestTimeout bounds how long a server-to-client request waits
+	// for a response before it is abandoned and removed from the pending set.
+	// Zero means requests wait until their context is done.
+	OutboundRequestTimeout time.Duration
+
+	// ShutdownTimeout bounds how long shutdown waits for in-flight handlers.
+	// Handlers see ShuttingDownFromContext as soon as shutdown begins; when
+	// the timeout expires their contexts are canceled, the transport is
+	// closed, and Run returns ErrShutdownTimeout without waiting for handlers
+	// that ignore their context. Zero waits for every handler to return.
+	ShutdownTimeout time.Duration
+
+	// ReadOnly restricts tools/call to tools annotated with readOnlyHint: true.
+	// Other tools, including those without annotations and names the tool
+	// provider does not list, are rejected with a permission_denied error
+	// result. Tools are still listed.
+	ReadOnly bool
+
+	// MaxContentBlocks caps the number of content blocks in a tools/call
+	// result. Extra blocks are dropped and replaced by a single text block
+	// noting how many were omitted. Zero means unlimited.
+	MaxContentBlocks int
+
+	// RequestInterceptor rewrites each incoming request or notification before
+	// it is dispatched, e.g. to inject default parameters. Returning nil drops
+	// the message; a dropped request gets no response.
+	RequestInterceptor func(*jsonrpc.Message) *jsonrpc.Message
+
+	// Sequential handles each message to completion before reading the next,
+	// instead of handling messages concurrently. This serializes all requests,
+	// for backends that cannot handle concurrency. Handlers must not wait on
+	// the client, e.g. with Roots, Sampling, or Elicit: the client's response
+	// would not be read until the handler returned.
+	Sequential bool
+
+	// Middleware wraps the handling of every incoming request and
+	// notification, the first entry outermost (optional).
+	Middleware []Middleware
+
+	// ResponseInterceptor rewrites each response before it is written,
+	// including error responses. Returning nil drops the response.
+	ResponseInterceptor func(*jsonrpc.Message) *jsonrpc.Message
+
+	// Capabilities overrides the capabilities advertised during initialize,
+	// e.g. to opt into resources.subscribe. When nil, capabilities are derived
+	// from the configured providers. Each advertised capability must be backed
+	// by its provider or New returns an error.
+	Capabilities *protocol.ServerCapabilities
+
+	// NotificationHandler is called for client notifications the server does
+	// not consume itself, and for notifications/roots/list_changed and
+	// notifications/cancelled after the server has acted on them (optional).
+	NotificationHandler func(ctx context.Context, method string, params json.RawMessage)
+
+	// CapabilityFilter adjusts the capabilities advertised to each client
+	// during initialize, given the client's initialize params and the
+	// capabilities the server would otherwise advertise (optional).
+	CapabilityFilter func(client protocol.InitializeParams, capabilities protocol.ServerCapabilities) protocol.ServerCapabilities
+
+	// ToolFilter selects the tools each client sees, given its initialize
+	// params, e.g. to hide sampling-based tools from clients without
+	// sampling. Tools it removes are also rejected by tools/call as unknown
+	// (optional).
+	ToolFilter func(client protocol.InitializeParams, tools []protocol.Tool) []protocol.Tool
+
+	// OutputDefaults derives the output.Defaults for each client from its
+	// initialize params, e.g. from hints in its _meta or its client name.
+	// Handlers read them with output.DefaultsFromContext, which falls back
+	// to output.StandardDefaults when this is nil (optional).
+	OutputDefaults func(client protocol.InitializeParams) output.Defaults
+
+	// WarnOnVersionMismatch sends a warning-level notifications/message once
+	// the client is initialized if the negotiated protocol version differs from
+	// the one the client requested. It is only sent to clients advertising the
+	// logging capability.
+	WarnOnVersionMismatch bool
+
+	// BuildInfo is added to every ping result under _meta.buildInfo, so
+	// operators can check which build is running. See ReadBuildInfo
+	// (optional).
+	BuildInfo *protocol.BuildInfo
+
+	// WarnOnUnknownCapabilities sends a warning-level notifications/message
+	// once the client is initialized if it advertised top-level capabilities
+	// this library does not know. It is only sent to clients advertising the
+	// logging capability.
+	WarnOnUnknownCapabilities bool
+
+	// StrictCapabilities rejects initialize with InvalidParams if the client
+	// advertises unknown top-level capabilities. The spec lets clients add
+	// capabilities freely, so this is off by default; experimental
+	// capabilities are always accepted.
+	StrictCapabilities bool
 }
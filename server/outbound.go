@@ -0,0 +1,179 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/amarbel-llc/go-lib-mcp/jsonrpc"
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+	"github.com/amarbel-llc/go-lib-mcp/transport"
+)
+
+// ErrConnectionClosed is returned by Request when the server shuts down
+// before the client responds.
+var ErrConnectionClosed = errors.New("connection closed")
+
+// ErrClientNotReady is returned by Request with Options.Sequential when the
+// client has not yet sent notifications/initialized.
+var ErrClientNotReady = errors.New("client has not finished initialization")
+
+// ErrTooManyPending is returned by Request when Options.MaxPendingRequests
+// requests are already awaiting a response from the client.
+var ErrTooManyPending = errors.New("too many pending requests")
+
+// outbound tracks server-to-client requests awaiting a response.
+type outbound struct {
+	ids        *jsonrpc.IDGenerator
+	maxPending int
+	timeout    time.Duration
+	mu         sync.Mutex
+	pending    map[string]chan *jsonrpc.Message
+	closed     bool
+}
+
+func newOutbound(maxPending int, timeout time.Duration) *outbound {
+	return &outbound{
+		ids:        jsonrpc.NewIDGenerator("srv"),
+		maxPending: maxPending,
+		timeout:    timeout,
+		pending:    make(map[string]chan *jsonrpc.Message),
+	}
+}
+
+// Request sends a request to the client and waits for its response.
+// It waits for the client to send notifications/initialized first; that
+// wait counts towards Options.OutboundRequestTimeout. With
+// Options.Sequential, where the notification cannot be read while a
+// handler runs, it fails at once with ErrClientNotReady instead.
+// Request IDs come from a prefixed generator so they never collide with the
+// IDs the client uses for its own requests.
+func (s *Server) Request(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	return s.session().request(ctx, method, params)
+}
+
+// request sends a request to the client on this session's connection.
+// Per the lifecycle in the spec, it waits until the client has sent
+// notifications/initialized before sending anything.
+func (sess *session) request(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	if sess.outbound.timeout > 0 {
+		// One budget covers the wait for the client and for its response.
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, sess.outbound.timeout)
+		defer cancel()
+	}
+
+	select {
+	case <-sess.ready.ch:
+	default:
+		if sess.handler.server.opts.Sequential {
+			return nil, fmt.Errorf("%s: %w", method, ErrClientNotReady)
+		}
+		select {
+		case <-sess.ready.ch:
+		case <-sess.shutdown.ch:
+			return nil, ErrConnectionClosed
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, fmt.Errorf("%s: client did not send %s: %w", method, protocol.MethodInitialized, ctx.Err())
+			}
+			return nil, ctx.Err()
+		}
+	}
+	return sess.outbound.call(ctx, sess.transport, "client", method, params)
+}
+
+// call writes a request to t and waits for the response to be delivered.
+// peer names the other side in timeout errors.
+func (o *outbound) call(ctx context.Context, t transport.Transport, peer, method string, params any) (json.RawMessage, error) {
+	id := o.ids.Next()
+	key := id.String()
+
+	msg, err := jsonrpc.NewRequest(id, method, params)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan *jsonrpc.Message, 1)
+	o.mu.Lock()
+	if o.closed {
+		o.mu.Unlock()
+		return nil, ErrConnectionClosed
+	}
+	if o.maxPending > 0 && len(o.pending) >= o.maxPending {
+		o.mu.Unlock()
+		return nil, ErrTooManyPending
+	}
+	o.pending[key] = ch
+	o.mu.Unlock()
+
+	if o.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.timeout)
+		defer cancel()
+	}
+
+	if err := t.Write(msg); err != nil {
+		o.remove(key)
+		return nil, err
+	}
+
+	select {
+	case <-ctx.Done():
+		o.remove(key)
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%s: no response from %s: %w", method, peer, ctx.Err())
+		}
+		return nil, ctx.Err()
+	case resp, ok := <-ch:
+		if !ok {
+			return nil, ErrConnectionClosed
+		}
+		if resp.Error != nil {
+			return nil, resp.Error
+		}
+		return resp.Result, nil
+	}
+}
+
+func (o *outbound) remove(key string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	delete(o.pending, key)
+}
+
+// deliver routes a response from the client to the waiting Request call.
+// Responses with unknown IDs are dropped.
+func (o *outbound) deliver(msg *jsonrpc.Message) {
+	key := msg.ID.String()
+
+	o.mu.Lock()
+	ch, ok := o.pending[key]
+	delete(o.pending, key)
+	o.mu.Unlock()
+
+	if ok {
+		ch <- msg
+	}
+}
+
+// close fails all pending requests and rejects new ones.
+func (o *outbound) close() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.closed = true
+	for key, ch := range o.pending {
+		close(ch)
+		delete(o.pending, key)
+	}
+}
+
+func (o *outbound) count() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.pending)
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amarbel-llc/go-lib-mcp/jsonrpc"
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+func TestRequestTooManyPending(t *testing.T) {
+	tr := newFakeTransport()
+	s, err := New(tr, Options{ServerName: "test", MaxPendingRequests: 3})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	initialize(t, s)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for i := 0; i < 3; i++ {
+		go s.Request(ctx, "roots/list", nil)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for s.session().outbound.count() < 3 {
+		if time.Now().After(deadline) {
+			t.Fatal("pending requests never reached the limit")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if _, err := s.Request(ctx, "roots/list", nil); !errors.Is(err, ErrTooManyPending) {
+		t.Fatalf("err = %v, want ErrTooManyPending", err)
+	}
+}
+
+func TestRequestTimeoutRemovesPending(t *testing.T) {
+	tr := newFakeTransport()
+	s, err := New(tr, Options{ServerName: "test", OutboundRequestTimeout: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	initialize(t, s)
+
+	_, err = s.Request(context.Background(), "roots/list", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want deadline exceeded", err)
+	}
+
+	if n := s.session().outbound.count(); n != 0 {
+		t.Fatalf("pending = %d, want 0 after timeout", n)
+	}
+}
+
+func TestRequestWaitsForInitialized(t *testing.T) {
+	tr := newFakeTransport()
+	s, err := New(tr, Options{ServerName: "test"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	h := s.session().handler
+
+	req, _ := jsonrpc.NewRequest(jsonrpc.NewNumberID(1), protocol.MethodInitialize, protocol.InitializeParams{
+		ProtocolVersion: protocol.ProtocolVersion,
+	})
+	if _, err := h.Handle(context.Background(), req); err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Request(ctx, "roots/list", nil)
+
+	time.Sleep(20 * time.Millisecond)
+	if n := tr.count("roots/list"); n != 0 {
+		t.Fatalf("sent %d requests before notifications/initialized", n)
+	}
+
+	initialized, _ := jsonrpc.NewNotification(protocol.MethodInitialized, nil)
+	h.Handle(context.Background(), initialized)
+
+	deadline := time.Now().Add(time.Second)
+	for tr.count("roots/list") == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("request not sent after notifications/initialized")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestRequestTimeoutCoversWaitForInitialized(t *testing.T) {
+	tr := newFakeTransport()
+	s, err := New(tr, Options{ServerName: "test", OutboundRequestTimeout: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	_, err = s.Request(context.Background(), "roots/list", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want deadline exceeded", err)
+	}
+	if n := tr.count("roots/list"); n != 0 {
+		t.Errorf("sent %d requests before notifications/initialized", n)
+	}
+}
+
+func TestSequentialRequestBeforeInitializedFails(t *testing.T) {
+	s, err := New(newFakeTransport(), Options{ServerName: "test", Sequential: true})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if _, err := s.Request(context.Background(), "roots/list", nil); !errors.Is(err, ErrClientNotReady) {
+		t.Fatalf("err = %v, want ErrClientNotReady", err)
+	}
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/amarbel-llc/go-lib-mcp/output"
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+// PageArgs is the conventional argument of a paged tool. Embed it in the
+// tool's argument struct and pass Cursor to Paged.
+type PageArgs struct {
+	// Cursor is the NextCursor of the previous page, or empty for the first.
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// Page is one page of a paged tool's results.
+type Page[T any] struct {
+	Items []T `json:"items"`
+
+	// NextCursor fetches the following page; it is empty on the last page.
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// pageCursor is the decoded form of an opaque page cursor.
+type pageCursor struct {
+	Offset int `json:"o"`
+}
+
+// Paged returns the page of items starting at cursor, holding at most
+// pageSize items (all remaining items if pageSize is zero). The cursor is
+// opaque to clients; an invalid one produces an error wrapping
+// ErrInvalidParams.
+func Paged[T any](items []T, cursor string, pageSize int) (Page[T], error) {
+	offset := 0
+	if cursor != "" {
+		var err error
+		if offset, err = decodeCursor(cursor); err != nil {
+			return Page[T]{}, err
+		}
+	}
+
+	limited := output.LimitArray(items, output.ArrayLimits{Limit: pageSize, Offset: offset})
+
+	page := Page[T]{Items: limited.Items}
+	if limited.Pagination.HasMore {
+		page.NextCursor = encodeCursor(offset + len(limited.Items))
+	}
+	return page, nil
+}
+
+// Result returns the page as a tool result, with the page as structured
+// content and its JSON encoding as text.
+func (p Page[T]) Result() *protocol.ToolCallResult {
+	if p.Items == nil {
+		p.Items = []T{}
+	}
+	return &protocol.ToolCallResult{
+		Content:           []protocol.ContentBlock{protocol.AutoContent(p)},
+		StructuredContent: p,
+	}
+}
+
+func encodeCursor(offset int) string {
+	data, _ := json.Marshal(pageCursor{Offset: offset})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(cursor string) (int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("%w: malformed cursor", ErrInvalidParams)
+	}
+	var c pageCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Offset < 0 {
+		return 0, fmt.Errorf("%w: malformed cursor", ErrInvalidParams)
+	}
+	return c.Offset, nil
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"context"
+
+	"github.com/amarbel-llc/go-lib-mcp/transport"
+)
+
+type peerKey struct{}
+
+// PeerInfo identifies the client on the other end of a connection.
+type PeerInfo struct {
+	// RemoteAddr is the client's network address, if the transport has one.
+	RemoteAddr string
+
+	// Identity is a caller-supplied identity, such as an authenticated subject.
+	Identity any
+}
+
+// PeerInfoProvider is implemented by transports that know who they are
+// connected to. The server attaches the PeerInfo to the context of every
+// request served over such a transport.
+type PeerInfoProvider interface {
+	PeerInfo() PeerInfo
+}
+
+// PeerFromContext returns the peer of the connection the current request
+// arrived on. Transports without a peer, such as stdio, report false.
+func PeerFromContext(ctx context.Context) (PeerInfo, bool) {
+	info, ok := ctx.Value(peerKey{}).(PeerInfo)
+	return info, ok
+}
+
+// WithPeerInfo wraps t so that requests served over it carry info.
+// Use it to attach an address or identity established while accepting
+// the connection.
+func WithPeerInfo(t transport.Transport, info PeerInfo) transport.Transport {
+	return &peerTransport{Transport: t, info: info}
+}
+
+type peerTransport struct {
+	transport.Transport
+	info PeerInfo
+}
+
+// PeerInfo implements PeerInfoProvider.
+func (t *peerTransport) PeerInfo() PeerInfo {
+	return t.info
+}
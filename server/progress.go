@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+type progressKey struct{}
+
+// ProgressReporter sends notifications/progress for the request being handled.
+// It is only available when the client supplied a progress token.
+type ProgressReporter struct {
+	token  json.RawMessage
+	notify func(method string, params any) error
+}
+
+// ProgressFromContext returns the progress reporter for the current request,
+// if the client asked for progress notifications.
+func ProgressFromContext(ctx context.Context) (*ProgressReporter, bool) {
+	p, ok := ctx.Value(progressKey{}).(*ProgressReporter)
+	return p, ok
+}
+
+func withProgress(ctx context.Context, meta *protocol.RequestMeta, notify func(string, any) error) context.Context {
+	if meta == nil || len(meta.ProgressToken) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, progressKey{}, &ProgressReporter{
+		token:  meta.ProgressToken,
+		notify: notify,
+	})
+}
+
+// Report sends a progress notification. Progress must increase with every
+// call; total may be zero if unknown.
+func (p *ProgressReporter) Report(progress, total float64) error {
+	return p.Send(protocol.ProgressNotification{Progress: progress, Total: total})
+}
+
+// ReportMessage sends a progress notification with a human-readable status,
+// e.g. "compiling (3/10): parsing". Total may be zero if unknown.
+func (p *ProgressReporter) ReportMessage(progress, total float64, message string) error {
+	return p.Send(protocol.ProgressNotification{Progress: progress, Total: total, Message: message})
+}
+
+// Send sends a progress notification, filling in the request's progress token.
+func (p *ProgressReporter) Send(n protocol.ProgressNotification) error {
+	n.ProgressToken = p.token
+	return p.notify(protocol.MethodProgress, n)
+}
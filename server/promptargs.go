@@ -0,0 +1,63 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// PromptArgs gives typed access to the arguments a PromptRenderer receives:
+//
+//	func render(ctx context.Context, args map[string]string) (*protocol.PromptGetResult, error) {
+//		a := server.PromptArgs(args)
+//		code, err := a.Require("code")
+//		...
+//	}
+//
+// Errors wrap ErrInvalidParams, so a renderer can return them as is and the
+// client receives an InvalidParams error.
+type PromptArgs map[string]string
+
+// Get returns the named argument, or def if it is missing or empty.
+func (a PromptArgs) Get(name, def string) string {
+	if v := a[name]; v != "" {
+		return v
+	}
+	return def
+}
+
+// Require returns the named argument, or an error if it is missing or empty.
+func (a PromptArgs) Require(name string) (string, error) {
+	v := a[name]
+	if v == "" {
+		return "", fmt.Errorf("%w: missing required argument %q", ErrInvalidParams, name)
+	}
+	return v, nil
+}
+
+// GetBool parses the named argument with strconv.ParseBool. A missing or
+// empty argument is false.
+func (a PromptArgs) GetBool(name string) (bool, error) {
+	v := a[name]
+	if v == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("%w: argument %q: %q is not a boolean", ErrInvalidParams, name, v)
+	}
+	return b, nil
+}
+
+// GetInt parses the named argument as a base-10 integer. A missing or empty
+// argument is zero.
+func (a PromptArgs) GetInt(name string) (int, error) {
+	v := a[name]
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("%w: argument %q: %q is not an integer", ErrInvalidParams, name, v)
+	}
+	return n, nil
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPromptArgs(t *testing.T) {
+	args := PromptArgs{"lang": "go", "verbose": "true", "limit": "10", "empty": ""}
+
+	if got := args.Get("lang", "python"); got != "go" {
+		t.Errorf("Get(lang) = %q, want go", got)
+	}
+	if got := args.Get("empty", "fallback"); got != "fallback" {
+		t.Errorf("Get(empty) = %q, want fallback", got)
+	}
+
+	if v, err := args.Require("lang"); err != nil || v != "go" {
+		t.Errorf("Require(lang) = %q, %v", v, err)
+	}
+	if _, err := args.Require("missing"); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("Require(missing) err = %v, want ErrInvalidParams", err)
+	}
+
+	if b, err := args.GetBool("verbose"); err != nil || !b {
+		t.Errorf("GetBool(verbose) = %v, %v", b, err)
+	}
+	if b, err := args.GetBool("missing"); err != nil || b {
+		t.Errorf("GetBool(missing) = %v, %v; want false, nil", b, err)
+	}
+
+	if n, err := args.GetInt("limit"); err != nil || n != 10 {
+		t.Errorf("GetInt(limit) = %d, %v", n, err)
+	}
+	if n, err := args.GetInt("missing"); err != nil || n != 0 {
+		t.Errorf("GetInt(missing) = %d, %v; want 0, nil", n, err)
+	}
+}
+
+func TestPromptArgsParseErrors(t *testing.T) {
+	args := PromptArgs{"verbose": "maybe", "limit": "ten"}
+
+	if _, err := args.GetBool("verbose"); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("GetBool(maybe) err = %v, want ErrInvalidParams", err)
+	}
+	if _, err := args.GetInt("limit"); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("GetInt(ten) err = %v, want ErrInvalidParams", err)
+	}
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+// promptResources exposes an inner prompt provider's prompts as resources.
+type promptResources struct {
+	inner   PromptProvider
+	baseURI string
+}
+
+// notifyingPromptResources forwards list-change callbacks to an inner
+// provider that supports them.
+type notifyingPromptResources struct {
+	*promptResources
+	ListChangeNotifier
+}
+
+// PromptsAsResources exposes each prompt of pp as a text/plain resource at
+// baseURI/{name}, for clients that read resources but not prompts.
+//
+// Resources take no arguments, so a prompt is rendered with an empty argument
+// map: arguments get whatever default the prompt applies when they are
+// missing, and prompts that require an argument fail to read. The text of the
+// rendered messages is joined with blank lines; non-text content is omitted.
+func PromptsAsResources(pp PromptProvider, baseURI string) ResourceProvider {
+	p := &promptResources{inner: pp, baseURI: strings.TrimSuffix(baseURI, "/")}
+	if n, ok := pp.(ListChangeNotifier); ok {
+		return &notifyingPromptResources{p, n}
+	}
+	return p
+}
+
+// ListResources implements ResourceProvider.
+func (p *promptResources) ListResources(ctx context.Context) ([]protocol.Resource, error) {
+	prompts, err := p.inner.ListPrompts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]protocol.Resource, 0, len(prompts))
+	for _, prompt := range prompts {
+		resources = append(resources, protocol.Resource{
+			URI:         p.uri(prompt.Name),
+			Name:        prompt.Name,
+			Description: prompt.Description,
+			MimeType:    "text/plain",
+		})
+	}
+	return resources, nil
+}
+
+// ReadResource implements ResourceProvider.
+func (p *promptResources) ReadResource(ctx context.Context, uri string) (*protocol.ResourceReadResult, error) {
+	escaped, ok := strings.CutPrefix(uri, p.baseURI+"/")
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownResource, uri)
+	}
+	name, err := url.PathUnescape(escaped)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownResource, uri)
+	}
+
+	result, err := p.inner.GetPrompt(ctx, name, map[string]string{})
+	if errors.Is(err, ErrUnknownPrompt) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownResource, uri)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var texts []string
+	for _, msg := range result.Messages {
+		if msg.Content.Type == "text" {
+			texts = append(texts, msg.Content.Text)
+		}
+	}
+
+	return &protocol.ResourceReadResult{
+		Contents: []protocol.ResourceContent{
+			{URI: uri, MimeType: "text/plain", Text: strings.Join(texts, "\n\n")},
+		},
+	}, nil
+}
+
+// ListResourceTemplates implements ResourceProvider.
+func (p *promptResources) ListResourceTemplates(ctx context.Context) ([]protocol.ResourceTemplate, error) {
+	return nil, nil
+}
+
+func (p *promptResources) uri(name string) string {
+	return p.baseURI + "/" + url.PathEscape(name)
+}
@@ -32,6 +32,24 @@ type ResourceProvider interface {
 	ListResourceTemplates(ctx context.Context) ([]protocol.ResourceTemplate, error)
 }
 
+// ListChangeNotifier is implemented by providers whose lists can change while
+// the server is running. The server installs a callback with OnListChanged and
+// sends the matching notifications/*/list_changed notification when it fires.
+type ListChangeNotifier interface {
+	// OnListChanged adds a function to call after the provider's list
+	// changes. Every added function is called, so a provider can be watched
+	// by a Server and by a provider wrapping it at the same time.
+	OnListChanged(fn func())
+}
+
+// ResourceDiffer is implemented by resource providers that can report how
+// their resource list changed. ResourceDiff returns the changes since the
+// previous call, or since the client last listed resources if that is more
+// recent, and starts a new baseline.
+type ResourceDiffer interface {
+	ResourceDiff() protocol.ResourceListDiff
+}
+
 // PromptProvider is implemented by servers that provide prompt templates.
 // Prompts are pre-defined message templates that can be instantiated with arguments.
 type PromptProvider interface {
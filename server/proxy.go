@@ -0,0 +1,317 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/amarbel-llc/go-lib-mcp/jsonrpc"
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+	"github.com/amarbel-llc/go-lib-mcp/transport"
+)
+
+// ProxyOptions configures a ProxyProvider.
+type ProxyOptions struct {
+	// Prefix is prepended to upstream tool and prompt names, e.g. "git_",
+	// so several upstreams can be aggregated without collisions. Resource
+	// URIs are forwarded unchanged.
+	Prefix string
+
+	// ClientInfo identifies the proxy to the upstream server.
+	// Defaults to {Name: "go-lib-mcp-proxy"}.
+	ClientInfo protocol.Implementation
+}
+
+// ProxyProvider forwards tools, resources, and prompts to an upstream MCP
+// server. It implements ToolProvider, ResourceProvider, and PromptProvider;
+// to also relay the upstream's list change notifications, install the
+// role-typed views Tools, Resources, and Prompts, each of which implements
+// ListChangeNotifier for its own list only:
+//
+//	server.New(t, server.Options{
+//		Tools:     proxy.Tools(),
+//		Resources: proxy.Resources(),
+//		Prompts:   proxy.Prompts(),
+//	})
+//
+// To proxy a server started with an executor.Executor, wrap the process
+// pipes: transport.NewStdioWithClosers(proc.Stdout, proc.Stdin, proc.Stdin).
+//
+// If the upstream disconnects, pending and later calls fail with
+// ErrConnectionClosed; Err reports why the connection ended.
+type ProxyProvider struct {
+	transport transport.Transport
+	opts      ProxyOptions
+	outbound  *outbound
+	upstream  protocol.InitializeResult
+	done      chan struct{}
+
+	mu       sync.Mutex
+	onChange map[string][]func() // keyed by list_changed method
+	err      error
+}
+
+// NewProxyProvider performs the initialize handshake with the upstream
+// server on t and starts relaying its messages. The proxy owns t and closes
+// it on Close.
+func NewProxyProvider(ctx context.Context, t transport.Transport, opts ProxyOptions) (*ProxyProvider, error) {
+	if opts.ClientInfo.Name == "" {
+		opts.ClientInfo.Name = "go-lib-mcp-proxy"
+	}
+
+	p := &ProxyProvider{
+		transport: t,
+		opts:      opts,
+		outbound:  newOutbound(0, 0),
+		done:      make(chan struct{}),
+		onChange:  make(map[string][]func()),
+	}
+	go p.readLoop()
+
+	raw, err := p.call(ctx, protocol.MethodInitialize, protocol.InitializeParams{
+		ProtocolVersion: protocol.ProtocolVersion,
+		ClientInfo:      opts.ClientInfo,
+	})
+	if err == nil {
+		err = json.Unmarshal(raw, &p.upstream)
+	}
+	if err == nil {
+		err = p.notify(protocol.MethodInitialized)
+	}
+	if err != nil {
+		p.Close()
+		return nil, fmt.Errorf("initializing upstream: %w", err)
+	}
+	return p, nil
+}
+
+// Upstream returns the upstream server's initialize result.
+func (p *ProxyProvider) Upstream() protocol.InitializeResult {
+	return p.upstream
+}
+
+// Close closes the connection to the upstream server.
+func (p *ProxyProvider) Close() error {
+	return p.transport.Close()
+}
+
+// Done is closed when the connection to the upstream server ends.
+func (p *ProxyProvider) Done() <-chan struct{} {
+	return p.done
+}
+
+// Err returns why the connection to the upstream server ended, or nil while
+// it is still open. A clean disconnect reports io.EOF.
+func (p *ProxyProvider) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+// Tools returns p as a ToolProvider whose OnListChanged fires when the
+// upstream's tool list changes.
+func (p *ProxyProvider) Tools() ToolProvider {
+	return proxyTools{p}
+}
+
+// Resources returns p as a ResourceProvider whose OnListChanged fires when
+// the upstream's resource list changes.
+func (p *ProxyProvider) Resources() ResourceProvider {
+	return proxyResources{p}
+}
+
+// Prompts returns p as a PromptProvider whose OnListChanged fires when the
+// upstream's prompt list changes.
+func (p *ProxyProvider) Prompts() PromptProvider {
+	return proxyPrompts{p}
+}
+
+type proxyTools struct{ *ProxyProvider }
+
+func (v proxyTools) OnListChanged(fn func()) {
+	v.addListener(protocol.MethodToolsListChanged, fn)
+}
+
+type proxyResources struct{ *ProxyProvider }
+
+func (v proxyResources) OnListChanged(fn func()) {
+	v.addListener(protocol.MethodResourcesListChanged, fn)
+}
+
+type proxyPrompts struct{ *ProxyProvider }
+
+func (v proxyPrompts) OnListChanged(fn func()) {
+	v.addListener(protocol.MethodPromptsListChanged, fn)
+}
+
+// addListener registers fn for the upstream notification method.
+func (p *ProxyProvider) addListener(method string, fn func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onChange[method] = append(p.onChange[method], fn)
+}
+
+// ListTools implements ToolProvider.
+func (p *ProxyProvider) ListTools(ctx context.Context) ([]protocol.Tool, error) {
+	var result protocol.ToolsListResult
+	if err := p.get(ctx, protocol.MethodToolsList, nil, &result); err != nil {
+		return nil, err
+	}
+	for i := range result.Tools {
+		result.Tools[i].Name = p.opts.Prefix + result.Tools[i].Name
+	}
+	return result.Tools, nil
+}
+
+// CallTool implements ToolProvider.
+func (p *ProxyProvider) CallTool(ctx context.Context, name string, args json.RawMessage) (*protocol.ToolCallResult, error) {
+	upstreamName, ok := strings.CutPrefix(name, p.opts.Prefix)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTool, name)
+	}
+
+	var result protocol.ToolCallResult
+	params := protocol.ToolCallParams{Name: upstreamName, Arguments: args}
+	if err := p.get(ctx, protocol.MethodToolsCall, params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListResources implements ResourceProvider.
+func (p *ProxyProvider) ListResources(ctx context.Context) ([]protocol.Resource, error) {
+	var result protocol.ResourcesListResult
+	if err := p.get(ctx, protocol.MethodResourcesList, nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Resources, nil
+}
+
+// ReadResource implements ResourceProvider.
+func (p *ProxyProvider) ReadResource(ctx context.Context, uri string) (*protocol.ResourceReadResult, error) {
+	var result protocol.ResourceReadResult
+	if err := p.get(ctx, protocol.MethodResourcesRead, protocol.ResourceReadParams{URI: uri}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListResourceTemplates implements ResourceProvider.
+func (p *ProxyProvider) ListResourceTemplates(ctx context.Context) ([]protocol.ResourceTemplate, error) {
+	var result protocol.ResourceTemplatesListResult
+	if err := p.get(ctx, protocol.MethodResourcesTemplates, nil, &result); err != nil {
+		return nil, err
+	}
+	return result.ResourceTemplates, nil
+}
+
+// ListPrompts implements PromptProvider.
+func (p *ProxyProvider) ListPrompts(ctx context.Context) ([]protocol.Prompt, error) {
+	var result protocol.PromptsListResult
+	if err := p.get(ctx, protocol.MethodPromptsList, nil, &result); err != nil {
+		return nil, err
+	}
+	for i := range result.Prompts {
+		result.Prompts[i].Name = p.opts.Prefix + result.Prompts[i].Name
+	}
+	return result.Prompts, nil
+}
+
+// GetPrompt implements PromptProvider.
+func (p *ProxyProvider) GetPrompt(ctx context.Context, name string, args map[string]string) (*protocol.PromptGetResult, error) {
+	upstreamName, ok := strings.CutPrefix(name, p.opts.Prefix)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownPrompt, name)
+	}
+
+	var result protocol.PromptGetResult
+	params := protocol.PromptGetParams{Name: upstreamName, Arguments: args}
+	if err := p.get(ctx, protocol.MethodPromptsGet, params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// get calls method upstream and decodes the result into v.
+func (p *ProxyProvider) get(ctx context.Context, method string, params any, v any) error {
+	raw, err := p.call(ctx, method, params)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("decoding upstream %s result: %w", method, err)
+	}
+	return nil
+}
+
+func (p *ProxyProvider) call(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	raw, err := p.outbound.call(ctx, p.transport, "upstream", method, params)
+	var rpcErr *jsonrpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.Code == jsonrpc.InvalidParams {
+		// Keep the upstream's verdict that the request was malformed.
+		return nil, fmt.Errorf("%w: %s", ErrInvalidParams, rpcErr.Message)
+	}
+	return raw, err
+}
+
+func (p *ProxyProvider) notify(method string) error {
+	msg, err := jsonrpc.NewNotification(method, nil)
+	if err != nil {
+		return err
+	}
+	return p.transport.Write(msg)
+}
+
+// readLoop routes upstream messages until the connection ends.
+func (p *ProxyProvider) readLoop() {
+	defer close(p.done)
+
+	for {
+		msg, err := p.transport.Read()
+		var msgErr *transport.MessageError
+		if errors.As(err, &msgErr) {
+			continue
+		}
+		if err != nil {
+			p.mu.Lock()
+			p.err = err
+			p.mu.Unlock()
+			p.outbound.close()
+			return
+		}
+
+		switch {
+		case msg.IsResponse():
+			p.outbound.deliver(msg)
+		case msg.IsRequest():
+			p.answer(msg)
+		case msg.Method == protocol.MethodToolsListChanged,
+			msg.Method == protocol.MethodResourcesListChanged,
+			msg.Method == protocol.MethodPromptsListChanged:
+			p.mu.Lock()
+			callbacks := slices.Clone(p.onChange[msg.Method])
+			p.mu.Unlock()
+			for _, fn := range callbacks {
+				fn()
+			}
+		}
+	}
+}
+
+// answer responds to a request from the upstream server. The proxy offers
+// no client capabilities, so only ping is supported.
+func (p *ProxyProvider) answer(msg *jsonrpc.Message) {
+	var resp *jsonrpc.Message
+	if msg.Method == protocol.MethodPing {
+		resp, _ = jsonrpc.NewResponse(msg.RequestID(), protocol.PingResult{})
+	} else {
+		resp, _ = jsonrpc.NewErrorResponse(msg.RequestID(), jsonrpc.MethodNotFound, "method not supported by proxy: "+msg.Method, nil)
+	}
+	if resp != nil {
+		p.transport.Write(resp)
+	}
+}
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+	"github.com/amarbel-llc/go-lib-mcp/transport"
+)
+
+func TestProxyProviderForwardsToUpstream(t *testing.T) {
+	toUpstream, fromProxy := io.Pipe()
+	toProxy, fromUpstream := io.Pipe()
+
+	tools := NewToolRegistry()
+	tools.Register("echo", "", nil, func(ctx context.Context, args json.RawMessage) (*protocol.ToolCallResult, error) {
+		return &protocol.ToolCallResult{Content: []protocol.ContentBlock{protocol.TextContent(string(args))}}, nil
+	})
+	upstream, err := New(transport.NewStdioWithCloser(toUpstream, fromUpstream, fromUpstream), Options{ServerName: "upstream", Tools: tools})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	go upstream.Run(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	proxy, err := NewProxyProvider(ctx, transport.NewStdioWithCloser(toProxy, fromProxy, fromProxy), ProxyOptions{Prefix: "up_"})
+	if err != nil {
+		t.Fatalf("NewProxyProvider: %v", err)
+	}
+	if name := proxy.Upstream().ServerInfo.Name; name != "upstream" {
+		t.Fatalf("upstream name = %q, want upstream", name)
+	}
+
+	list, err := proxy.ListTools(ctx)
+	if err != nil {
+		t.Fatalf("ListTools: %v", err)
+	}
+	if len(list) != 1 || list[0].Name != "up_echo" {
+		t.Fatalf("tools = %+v, want up_echo", list)
+	}
+
+	result, err := proxy.CallTool(ctx, "up_echo", json.RawMessage(`{"x":1}`))
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if got := result.Content[0].Text; got != `{"x":1}` {
+		t.Fatalf("result = %q, want the arguments echoed", got)
+	}
+
+	if _, err := proxy.CallTool(ctx, "echo", nil); !errors.Is(err, ErrUnknownTool) {
+		t.Fatalf("unprefixed call err = %v, want ErrUnknownTool", err)
+	}
+
+	// The upstream going away fails calls instead of hanging them.
+	fromUpstream.Close()
+	<-proxy.Done()
+	if _, err := proxy.CallTool(ctx, "up_echo", nil); !errors.Is(err, ErrConnectionClosed) {
+		t.Fatalf("call after disconnect err = %v, want ErrConnectionClosed", err)
+	}
+	if !errors.Is(proxy.Err(), io.EOF) {
+		t.Fatalf("Err = %v, want io.EOF", proxy.Err())
+	}
+	proxy.Close()
+}
+
+func TestProxyProviderRoutesListChangesByRole(t *testing.T) {
+	toUpstream, fromProxy := io.Pipe()
+	toProxy, fromUpstream := io.Pipe()
+
+	tools := NewToolRegistry()
+	prompts := NewPromptRegistry()
+	upstream, err := New(transport.NewStdioWithCloser(toUpstream, fromUpstream, fromUpstream), Options{ServerName: "upstream", Tools: tools, Prompts: prompts})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	go upstream.Run(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	proxy, err := NewProxyProvider(ctx, transport.NewStdioWithCloser(toProxy, fromProxy, fromProxy), ProxyOptions{})
+	if err != nil {
+		t.Fatalf("NewProxyProvider: %v", err)
+	}
+	defer proxy.Close()
+
+	toolsChanged := make(chan struct{}, 1)
+	promptsChanged := make(chan struct{}, 1)
+	proxy.Tools().(ListChangeNotifier).OnListChanged(func() { toolsChanged <- struct{}{} })
+	proxy.Prompts().(ListChangeNotifier).OnListChanged(func() { promptsChanged <- struct{}{} })
+
+	tools.Register("echo", "", nil, noopTool)
+
+	select {
+	case <-toolsChanged:
+	case <-ctx.Done():
+		t.Fatal("tools view was not notified")
+	}
+	select {
+	case <-promptsChanged:
+		t.Fatal("prompts view was notified of a tool list change")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Redacted replaces values removed by a Redactor.
+const Redacted = "***"
+
+// Redactor returns a function that replaces the values at the given JSON
+// pointers (RFC 6901, e.g. "/password" or "/auth/token") with "***", for
+// logging tool arguments and other payloads without leaking secrets.
+// Pointers that do not resolve in a document are ignored, and the rest of
+// the document is kept, although object keys come back sorted. A document
+// that is not valid JSON is replaced entirely, since its secrets cannot be
+// located. Redactor panics if a path is not a valid JSON pointer.
+func Redactor(paths ...string) func(json.RawMessage) json.RawMessage {
+	pointers := make([][]string, len(paths))
+	for i, path := range paths {
+		tokens, err := parsePointer(path)
+		if err != nil {
+			panic(fmt.Sprintf("server.Redactor: %v", err))
+		}
+		pointers[i] = tokens
+	}
+
+	return func(raw json.RawMessage) json.RawMessage {
+		if len(raw) == 0 || len(pointers) == 0 {
+			return raw
+		}
+
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		var doc any
+		if err := dec.Decode(&doc); err != nil {
+			return json.RawMessage(strconv.Quote(Redacted))
+		}
+
+		for _, tokens := range pointers {
+			doc = redactAt(doc, tokens)
+		}
+
+		out, err := json.Marshal(doc)
+		if err != nil {
+			return json.RawMessage(strconv.Quote(Redacted))
+		}
+		return out
+	}
+}
+
+var pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+// parsePointer splits a JSON pointer into its unescaped reference tokens.
+func parsePointer(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q: must start with /", path)
+	}
+
+	tokens := strings.Split(path[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = pointerUnescaper.Replace(token)
+	}
+	return tokens, nil
+}
+
+// redactAt returns doc with the value at tokens replaced by Redacted.
+func redactAt(doc any, tokens []string) any {
+	if len(tokens) == 0 {
+		return Redacted
+	}
+
+	switch v := doc.(type) {
+	case map[string]any:
+		if child, ok := v[tokens[0]]; ok {
+			v[tokens[0]] = redactAt(child, tokens[1:])
+		}
+	case []any:
+		i, err := strconv.Atoi(tokens[0])
+		if err == nil && i >= 0 && i < len(v) {
+			v[i] = redactAt(v[i], tokens[1:])
+		}
+	}
+	return doc
+}
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRedactor(t *testing.T) {
+	redact := Redactor("/password", "/auth/token", "/keys/1", "/a~1b", "/missing/deep")
+
+	in := json.RawMessage(`{"user":"ann","password":"hunter2","auth":{"token":"abc","kind":"bearer"},"keys":["k0","k1"],"a/b":1,"n":12345678901234567890}`)
+	got := string(redact(in))
+	want := `{"a/b":"***","auth":{"kind":"bearer","token":"***"},"keys":["k0","***"],"n":12345678901234567890,"password":"***","user":"ann"}`
+	if got != want {
+		t.Fatalf("redacted =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRedactorMalformedInput(t *testing.T) {
+	redact := Redactor("/password")
+
+	if got := string(redact(json.RawMessage(`{"password": "x"`))); got != `"***"` {
+		t.Fatalf("malformed input redacted to %s, want the whole document replaced", got)
+	}
+	if got := redact(nil); got != nil {
+		t.Fatalf("nil input redacted to %s, want nil", got)
+	}
+}
+
+func TestRedactorRejectsInvalidPointer(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for a pointer without a leading slash")
+		}
+	}()
+	Redactor("password")
+}
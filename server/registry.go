@@ -3,7 +3,13 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/amarbel-llc/go-lib-mcp/protocol"
 )
@@ -11,13 +17,59 @@ import (
 // ToolRegistry is a helper for building tool providers.
 // It maintains a map of tool names to handlers and implements the ToolProvider interface.
 type ToolRegistry struct {
+	mu       sync.RWMutex
 	tools    []protocol.Tool
 	handlers map[string]ToolHandler
+	onChange []func()
+	notFound func(name string) *protocol.ToolCallResult
+	suggest  bool
+	sorted   bool
 }
 
 // ToolHandler is a function that handles tool invocations.
 type ToolHandler func(ctx context.Context, args json.RawMessage) (*protocol.ToolCallResult, error)
 
+// ToolOption customizes a tool's definition at registration.
+type ToolOption func(*protocol.Tool)
+
+// WithAnnotations sets the tool's behavior annotations.
+func WithAnnotations(annotations protocol.ToolAnnotations) ToolOption {
+	return func(t *protocol.Tool) {
+		t.Annotations = &annotations
+	}
+}
+
+// ReadOnly marks the tool with readOnlyHint: true, keeping any other annotations.
+func ReadOnly() ToolOption {
+	return func(t *protocol.Tool) {
+		if t.Annotations == nil {
+			t.Annotations = &protocol.ToolAnnotations{}
+		}
+		readOnly := true
+		t.Annotations.ReadOnlyHint = &readOnly
+	}
+}
+
+// WithCategory sets the tool's _meta.category grouping hint.
+func WithCategory(category string) ToolOption {
+	return func(t *protocol.Tool) {
+		if t.Meta == nil {
+			t.Meta = &protocol.ToolMeta{}
+		}
+		t.Meta.Category = category
+	}
+}
+
+// WithTags appends to the tool's _meta.tags grouping hints.
+func WithTags(tags ...string) ToolOption {
+	return func(t *protocol.Tool) {
+		if t.Meta == nil {
+			t.Meta = &protocol.ToolMeta{}
+		}
+		t.Meta.Tags = append(t.Meta.Tags, tags...)
+	}
+}
+
 // NewToolRegistry creates a new empty tool registry.
 func NewToolRegistry() *ToolRegistry {
 	return &ToolRegistry{
@@ -25,40 +77,196 @@ func NewToolRegistry() *ToolRegistry {
 	}
 }
 
-// Register adds a tool to the registry.
-func (r *ToolRegistry) Register(name, description string, schema json.RawMessage, handler ToolHandler) {
-	r.tools = append(r.tools, protocol.Tool{
+// Register adds a tool to the registry. It registers nothing and returns an
+// error if the name is already taken (wrapping ErrDuplicateTool) or if
+// examples attached with WithExamples do not fit the schema.
+// It is safe to call while the server is running; the client is notified of the change.
+func (r *ToolRegistry) Register(name, description string, schema json.RawMessage, handler ToolHandler, opts ...ToolOption) error {
+	tool := protocol.Tool{
 		Name:        name,
 		Description: description,
 		InputSchema: schema,
-	})
+	}
+	for _, opt := range opts {
+		opt(&tool)
+	}
+	if err := validateExamples(tool.InputSchema); err != nil {
+		return fmt.Errorf("tool %s: %w", name, err)
+	}
+
+	r.mu.Lock()
+	if _, ok := r.handlers[name]; ok {
+		r.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrDuplicateTool, name)
+	}
+	r.tools = append(r.tools, tool)
 	r.handlers[name] = handler
+	onChange := r.onChange
+	r.mu.Unlock()
+
+	for _, fn := range onChange {
+		fn()
+	}
+	return nil
+}
+
+// Unregister removes a tool from the registry. It returns an error wrapping
+// ErrUnknownTool if no tool has that name.
+// It is safe to call while the server is running; the client is notified of the change.
+func (r *ToolRegistry) Unregister(name string) error {
+	r.mu.Lock()
+	if _, ok := r.handlers[name]; !ok {
+		r.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrUnknownTool, name)
+	}
+	delete(r.handlers, name)
+	r.tools = slices.DeleteFunc(r.tools, func(t protocol.Tool) bool {
+		return t.Name == name
+	})
+	onChange := r.onChange
+	r.mu.Unlock()
+
+	for _, fn := range onChange {
+		fn()
+	}
+	return nil
+}
+
+// OnListChanged implements ListChangeNotifier.
+func (r *ToolRegistry) OnListChanged(fn func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onChange = append(r.onChange, fn)
+}
+
+// SortByName makes ListTools return tools sorted by name instead of in
+// registration order.
+func (r *ToolRegistry) SortByName() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sorted = true
 }
 
 // ListTools implements ToolProvider.
 func (r *ToolRegistry) ListTools(ctx context.Context) ([]protocol.Tool, error) {
-	return r.tools, nil
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tools := slices.Clone(r.tools)
+	if r.sorted {
+		slices.SortStableFunc(tools, func(a, b protocol.Tool) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+	}
+	return tools, nil
+}
+
+// Tools returns a copy of the registered tools, in registration order.
+func (r *ToolRegistry) Tools() []protocol.Tool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.tools)
+}
+
+// Has reports whether a tool with the given name is registered.
+func (r *ToolRegistry) Has(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.handlers[name]
+	return ok
 }
 
 // CallTool implements ToolProvider.
 func (r *ToolRegistry) CallTool(ctx context.Context, name string, args json.RawMessage) (*protocol.ToolCallResult, error) {
+	r.mu.RLock()
 	handler, ok := r.handlers[name]
+	r.mu.RUnlock()
 	if !ok {
-		return protocol.ErrorResult(fmt.Sprintf("unknown tool: %s", name)), nil
+		return r.notFoundResult(name, nil), nil
 	}
 	return handler(ctx, args)
 }
 
+// notFoundResult builds the result for a call to an unknown tool, using the
+// handler set with SetNotFoundHandler if any. Suggestions are limited to
+// tools visible reports true for; a nil visible allows every tool.
+func (r *ToolRegistry) notFoundResult(name string, visible func(string) bool) *protocol.ToolCallResult {
+	r.mu.RLock()
+	notFound := r.notFound
+	r.mu.RUnlock()
+	if notFound != nil {
+		return notFound(name)
+	}
+	return protocol.ErrorResult(r.unknownToolMessage(name, visible))
+}
+
+// EnableSuggestions makes the default unknown-tool result suggest the closest
+// registered tool name, e.g. "unknown tool: get_tme; did you mean get_time?".
+// A handler installed with SetNotFoundHandler still takes priority.
+func (r *ToolRegistry) EnableSuggestions() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.suggest = true
+}
+
+func (r *ToolRegistry) unknownToolMessage(name string, visible func(string) bool) string {
+	msg := fmt.Sprintf("unknown tool: %s", name)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if !r.suggest {
+		return msg
+	}
+
+	names := make([]string, 0, len(r.tools))
+	for _, t := range r.tools {
+		if visible == nil || visible(t.Name) {
+			names = append(names, t.Name)
+		}
+	}
+
+	if closest, ok := closestName(name, names); ok {
+		msg += fmt.Sprintf("; did you mean %s?", closest)
+	}
+	return msg
+}
+
+// SetNotFoundHandler sets the function that builds the result returned when
+// CallTool is asked for an unregistered tool. Passing nil restores the default
+// "unknown tool" error result.
+func (r *ToolRegistry) SetNotFoundHandler(fn func(name string) *protocol.ToolCallResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.notFound = fn
+}
+
 // ResourceRegistry is a helper for building resource providers.
 type ResourceRegistry struct {
+	mu        sync.RWMutex
 	resources []protocol.Resource
 	templates []protocol.ResourceTemplate
 	readers   map[string]ResourceReader
+	matchers  []templateReader
+	caches    map[string]*resourceCache
+	timeout   time.Duration
+	onChange  []func()
+	sorted    bool
+
+	subscriptionMode SubscriptionMode
+
+	baselineMu sync.Mutex
+	baseline   map[string]protocol.Resource
 }
 
 // ResourceReader is a function that reads resource content.
 type ResourceReader func(ctx context.Context, uri string) (*protocol.ResourceReadResult, error)
 
+// templateReader pairs a parsed resource template with its reader.
+type templateReader struct {
+	template *uriTemplate
+	reader   ResourceReader
+}
+
 // NewResourceRegistry creates a new empty resource registry.
 func NewResourceRegistry() *ResourceRegistry {
 	return &ResourceRegistry{
@@ -67,69 +275,426 @@ func NewResourceRegistry() *ResourceRegistry {
 }
 
 // RegisterResource adds a static resource to the registry.
+// It is safe to call while the server is running; the client is notified of the change.
 func (r *ResourceRegistry) RegisterResource(resource protocol.Resource, reader ResourceReader) {
+	r.register(resource, reader, nil)
+}
+
+func (r *ResourceRegistry) register(resource protocol.Resource, reader ResourceReader, cache *resourceCache) {
+	r.mu.Lock()
 	r.resources = append(r.resources, resource)
 	r.readers[resource.URI] = reader
+	if cache != nil {
+		if r.caches == nil {
+			r.caches = make(map[string]*resourceCache)
+		}
+		r.caches[resource.URI] = cache
+	} else {
+		delete(r.caches, resource.URI)
+	}
+	onChange := r.onChange
+	r.mu.Unlock()
+
+	for _, fn := range onChange {
+		fn()
+	}
 }
 
-// RegisterTemplate adds a resource template to the registry.
-func (r *ResourceRegistry) RegisterTemplate(template protocol.ResourceTemplate, reader ResourceReader) {
+// Unregister removes a static resource from the registry. It returns an
+// error wrapping ErrUnknownResource if no resource has that URI.
+// It is safe to call while the server is running; the client is notified of the change.
+func (r *ResourceRegistry) Unregister(uri string) error {
+	r.mu.Lock()
+	if _, ok := r.readers[uri]; !ok {
+		r.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrUnknownResource, uri)
+	}
+	delete(r.readers, uri)
+	delete(r.caches, uri)
+	r.resources = slices.DeleteFunc(r.resources, func(res protocol.Resource) bool {
+		return res.URI == uri
+	})
+	onChange := r.onChange
+	r.mu.Unlock()
+
+	for _, fn := range onChange {
+		fn()
+	}
+	return nil
+}
+
+// RegisterTemplate adds a resource template to the registry. ReadResource
+// calls reader for URIs that match the template and no static resource;
+// the reader gets the template variables from TemplateVarsFromContext.
+// Templates are matched in registration order.
+//
+// Each expression must be a single {var}, matching within a path segment,
+// or {+var}, matching anything including slashes. Other RFC 6570 forms
+// return an error. A nil reader registers the template for listing only.
+func (r *ResourceRegistry) RegisterTemplate(template protocol.ResourceTemplate, reader ResourceReader) error {
+	var matcher *uriTemplate
+	if reader != nil {
+		var err error
+		if matcher, err = parseURITemplate(template.URITemplate); err != nil {
+			return err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.templates = append(r.templates, template)
-	// For templates, we can't pre-register the reader since URIs are dynamic
-	// Users should handle template URIs in their reader implementation
+	if matcher != nil {
+		r.matchers = append(r.matchers, templateReader{template: matcher, reader: reader})
+	}
+	return nil
+}
+
+// OnListChanged implements ListChangeNotifier.
+func (r *ResourceRegistry) OnListChanged(fn func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onChange = append(r.onChange, fn)
+}
+
+// SortByURI makes ListResources and ListResourceTemplates return entries
+// sorted by URI (or URI template) instead of in registration order.
+func (r *ResourceRegistry) SortByURI() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sorted = true
 }
 
 // ListResources implements ResourceProvider.
 func (r *ResourceRegistry) ListResources(ctx context.Context) ([]protocol.Resource, error) {
-	return r.resources, nil
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	resources := slices.Clone(r.resources)
+	if r.sorted {
+		slices.SortStableFunc(resources, func(a, b protocol.Resource) int {
+			return strings.Compare(a.URI, b.URI)
+		})
+	}
+	r.setBaseline(resources)
+	return resources, nil
+}
+
+// Resources returns a copy of the registered static resources, in registration order.
+func (r *ResourceRegistry) Resources() []protocol.Resource {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.resources)
+}
+
+// Templates returns a copy of the registered resource templates, in registration order.
+func (r *ResourceRegistry) Templates() []protocol.ResourceTemplate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.templates)
+}
+
+// Has reports whether a static resource with the given URI is registered.
+func (r *ResourceRegistry) Has(uri string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.readers[uri]
+	return ok
+}
+
+// ResourceInfo returns the metadata a static resource was registered with,
+// without reading it. It reports false for URIs only served by a template,
+// whose metadata is not known until they are read.
+func (r *ResourceRegistry) ResourceInfo(uri string) (protocol.Resource, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, res := range r.resources {
+		if res.URI == uri {
+			return res, true
+		}
+	}
+	return protocol.Resource{}, false
 }
 
 // ReadResource implements ResourceProvider.
 func (r *ResourceRegistry) ReadResource(ctx context.Context, uri string) (*protocol.ResourceReadResult, error) {
-	reader, ok := r.readers[uri]
+	base, lines, ranged := parseLineFragment(uri)
+
+	r.mu.RLock()
+	reader, vars, ok := r.resolve(uri)
+	if !ok && ranged {
+		reader, vars, ok = r.resolve(base)
+	} else {
+		ranged = false
+	}
+	timeout := r.timeout
+	r.mu.RUnlock()
 	if !ok {
-		return nil, fmt.Errorf("unknown resource: %s", uri)
+		return nil, fmt.Errorf("%w: %s", ErrUnknownResource, uri)
+	}
+
+	readURI := uri
+	if ranged {
+		readURI = base
 	}
-	return reader(ctx, uri)
+	if vars != nil {
+		ctx = context.WithValue(ctx, templateVarsKey{}, vars)
+	}
+	result, err := callWithTimeout(ctx, timeout, "reading resource "+uri, func(ctx context.Context) (*protocol.ResourceReadResult, error) {
+		return reader(ctx, readURI)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result != nil {
+		if err := result.Validate(); err != nil {
+			return nil, err
+		}
+		if ranged {
+			result = sliceResult(result, uri, lines)
+		}
+	}
+	return result, nil
+}
+
+// resolve finds the reader for uri: a static resource first, then the first
+// matching template. r.mu must be held.
+func (r *ResourceRegistry) resolve(uri string) (ResourceReader, map[string]string, bool) {
+	if reader, ok := r.readers[uri]; ok {
+		return reader, nil, true
+	}
+	for _, m := range r.matchers {
+		if vars, ok := m.template.match(uri); ok {
+			return m.reader, vars, true
+		}
+	}
+	return nil, nil, false
+}
+
+// SetTimeout bounds how long ReadResource waits for a reader.
+// Zero (the default) disables the bound.
+func (r *ResourceRegistry) SetTimeout(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.timeout = d
 }
 
 // ListResourceTemplates implements ResourceProvider.
 func (r *ResourceRegistry) ListResourceTemplates(ctx context.Context) ([]protocol.ResourceTemplate, error) {
-	return r.templates, nil
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	templates := slices.Clone(r.templates)
+	if r.sorted {
+		slices.SortStableFunc(templates, func(a, b protocol.ResourceTemplate) int {
+			return strings.Compare(a.URITemplate, b.URITemplate)
+		})
+	}
+	return templates, nil
 }
 
 // PromptRegistry is a helper for building prompt providers.
 type PromptRegistry struct {
+	mu        sync.RWMutex
 	prompts   []protocol.Prompt
 	renderers map[string]PromptRenderer
+	dynamic   map[string]PromptArgumentsFunc
+	timeout   time.Duration
+	onChange  []func()
+	sorted    bool
 }
 
 // PromptRenderer is a function that renders a prompt with arguments.
 type PromptRenderer func(ctx context.Context, args map[string]string) (*protocol.PromptGetResult, error)
 
+// PromptArgumentsFunc produces a prompt's current arguments, for prompts
+// whose valid arguments depend on runtime state.
+type PromptArgumentsFunc func(ctx context.Context) ([]protocol.PromptArgument, error)
+
 // NewPromptRegistry creates a new empty prompt registry.
 func NewPromptRegistry() *PromptRegistry {
 	return &PromptRegistry{
 		renderers: make(map[string]PromptRenderer),
+		dynamic:   make(map[string]PromptArgumentsFunc),
 	}
 }
 
 // Register adds a prompt to the registry.
+// It is safe to call while the server is running; the client is notified of the change.
 func (r *PromptRegistry) Register(prompt protocol.Prompt, renderer PromptRenderer) {
+	r.register(prompt, renderer, nil)
+}
+
+func (r *PromptRegistry) register(prompt protocol.Prompt, renderer PromptRenderer, argsFn PromptArgumentsFunc) {
+	r.mu.Lock()
 	r.prompts = append(r.prompts, prompt)
 	r.renderers[prompt.Name] = renderer
+	if argsFn != nil {
+		r.dynamic[prompt.Name] = argsFn
+	} else {
+		delete(r.dynamic, prompt.Name)
+	}
+	onChange := r.onChange
+	r.mu.Unlock()
+
+	for _, fn := range onChange {
+		fn()
+	}
+}
+
+// RegisterDynamic adds a prompt whose arguments are produced by argsFn each
+// time prompts are listed or a prompt is rendered, so the advertised
+// arguments track runtime state such as available models or projects.
+// An error from argsFn fails the request with an internal error.
+func (r *PromptRegistry) RegisterDynamic(name, description string, argsFn PromptArgumentsFunc, renderer PromptRenderer) {
+	r.register(protocol.Prompt{Name: name, Description: description}, renderer, argsFn)
+}
+
+// resolveArguments fills in the arguments of dynamic prompts.
+func (r *PromptRegistry) resolveArguments(ctx context.Context, prompt *protocol.Prompt, argsFn PromptArgumentsFunc) error {
+	arguments, err := argsFn(ctx)
+	if err != nil {
+		return fmt.Errorf("listing arguments of prompt %s: %w", prompt.Name, err)
+	}
+	prompt.Arguments = arguments
+	return nil
+}
+
+// OnListChanged implements ListChangeNotifier.
+func (r *PromptRegistry) OnListChanged(fn func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onChange = append(r.onChange, fn)
+}
+
+// SortByName makes ListPrompts return prompts sorted by name instead of in
+// registration order.
+func (r *PromptRegistry) SortByName() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sorted = true
 }
 
 // ListPrompts implements PromptProvider.
 func (r *PromptRegistry) ListPrompts(ctx context.Context) ([]protocol.Prompt, error) {
-	return r.prompts, nil
+	r.mu.RLock()
+	prompts := slices.Clone(r.prompts)
+	dynamic := maps.Clone(r.dynamic)
+	sorted := r.sorted
+	r.mu.RUnlock()
+
+	for i := range prompts {
+		if argsFn, ok := dynamic[prompts[i].Name]; ok {
+			if err := r.resolveArguments(ctx, &prompts[i], argsFn); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if sorted {
+		slices.SortStableFunc(prompts, func(a, b protocol.Prompt) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+	}
+	return prompts, nil
+}
+
+// Prompts returns a copy of the registered prompts, in registration order.
+func (r *PromptRegistry) Prompts() []protocol.Prompt {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.prompts)
+}
+
+// Has reports whether a prompt with the given name is registered.
+func (r *PromptRegistry) Has(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.renderers[name]
+	return ok
 }
 
 // GetPrompt implements PromptProvider.
 func (r *PromptRegistry) GetPrompt(ctx context.Context, name string, args map[string]string) (*protocol.PromptGetResult, error) {
+	r.mu.RLock()
 	renderer, ok := r.renderers[name]
+	argsFn := r.dynamic[name]
+	timeout := r.timeout
+	prompt := protocol.Prompt{Name: name}
+	for _, p := range r.prompts {
+		if p.Name == name {
+			prompt.Arguments = p.Arguments
+		}
+	}
+	r.mu.RUnlock()
 	if !ok {
-		return nil, fmt.Errorf("unknown prompt: %s", name)
+		return nil, fmt.Errorf("%w: %s", ErrUnknownPrompt, name)
+	}
+	if argsFn != nil {
+		if err := r.resolveArguments(ctx, &prompt, argsFn); err != nil {
+			return nil, err
+		}
+	}
+	arguments := prompt.Arguments
+	if err := validateEnumArgs(arguments, args); err != nil {
+		return nil, err
+	}
+	return callWithTimeout(ctx, timeout, "rendering prompt "+name, func(ctx context.Context) (*protocol.PromptGetResult, error) {
+		return renderer(ctx, args)
+	})
+}
+
+// validateEnumArgs checks that each provided argument with an Enum is one of
+// its allowed values.
+func validateEnumArgs(arguments []protocol.PromptArgument, args map[string]string) error {
+	for _, arg := range arguments {
+		if len(arg.Enum) == 0 {
+			continue
+		}
+		value, ok := args[arg.Name]
+		if !ok || slices.Contains(arg.Enum, value) {
+			continue
+		}
+		return fmt.Errorf("%w: argument %s must be one of %s, got %q",
+			ErrInvalidParams, arg.Name, strings.Join(arg.Enum, ", "), value)
+	}
+	return nil
+}
+
+// SetTimeout bounds how long GetPrompt waits for a renderer.
+// Zero (the default) disables the bound.
+func (r *PromptRegistry) SetTimeout(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.timeout = d
+}
+
+// callWithTimeout calls fn with a context that expires after d. Renderers and
+// readers should honor ctx, but the caller stops waiting at the deadline even
+// if fn does not return. A zero d calls fn directly.
+func callWithTimeout[T any](ctx context.Context, d time.Duration, what string, fn func(context.Context) (T, error)) (T, error) {
+	if d <= 0 {
+		return fn(ctx)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+
+	type outcome struct {
+		val T
+		err error
+	}
+
+	done := make(chan outcome, 1)
+	go func() {
+		val, err := fn(ctx)
+		done <- outcome{val, err}
+	}()
+
+	select {
+	case o := <-done:
+		return o.val, o.err
+	case <-ctx.Done():
+		var zero T
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return zero, fmt.Errorf("%s: timed out after %s: %w", what, d, ctx.Err())
+		}
+		return zero, ctx.Err()
 	}
-	return renderer(ctx, args)
 }
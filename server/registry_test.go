@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+func TestResourceRegistryTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	readerErr := make(chan error, 1)
+
+	resources := NewResourceRegistry()
+	resources.SetTimeout(20 * time.Millisecond)
+	resources.RegisterResource(protocol.Resource{URI: "file:///slow"}, func(ctx context.Context, uri string) (*protocol.ResourceReadResult, error) {
+		// Hang past the timeout without watching ctx, like a stuck reader.
+		<-release
+		readerErr <- ctx.Err()
+		return nil, nil
+	})
+
+	start := time.Now()
+	_, err := resources.ReadResource(context.Background(), "file:///slow")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("ReadResource took %s, want about the 20ms timeout", elapsed)
+	}
+
+	release <- struct{}{}
+	if err := <-readerErr; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("reader ctx err = %v, want it canceled by the deadline", err)
+	}
+}
+
+func TestPromptRegistryTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	rendererErr := make(chan error, 1)
+
+	prompts := NewPromptRegistry()
+	prompts.SetTimeout(20 * time.Millisecond)
+	prompts.Register(protocol.Prompt{Name: "slow"}, func(ctx context.Context, args map[string]string) (*protocol.PromptGetResult, error) {
+		<-release
+		rendererErr <- ctx.Err()
+		return nil, nil
+	})
+
+	start := time.Now()
+	_, err := prompts.GetPrompt(context.Background(), "slow", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("GetPrompt took %s, want about the 20ms timeout", elapsed)
+	}
+
+	release <- struct{}{}
+	if err := <-rendererErr; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("renderer ctx err = %v, want it canceled by the deadline", err)
+	}
+}
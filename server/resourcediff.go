@@ -0,0 +1,48 @@
+package server
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+// ResourceDiff implements ResourceDiffer. It reports the static resources
+// added or changed, and the URIs removed, since the previous call or since
+// the last ListResources, whichever is more recent.
+func (r *ResourceRegistry) ResourceDiff() protocol.ResourceListDiff {
+	r.mu.RLock()
+	current := make(map[string]protocol.Resource, len(r.resources))
+	var diff protocol.ResourceListDiff
+	r.baselineMu.Lock()
+	for _, res := range r.resources {
+		current[res.URI] = res
+		if prev, ok := r.baseline[res.URI]; !ok || !reflect.DeepEqual(prev, res) {
+			diff.Added = append(diff.Added, res)
+		}
+	}
+	r.mu.RUnlock()
+	defer r.baselineMu.Unlock()
+
+	for uri := range r.baseline {
+		if _, ok := current[uri]; !ok {
+			diff.Removed = append(diff.Removed, uri)
+		}
+	}
+	slices.Sort(diff.Removed)
+
+	r.baseline = current
+	return diff
+}
+
+// setBaseline records the resources a client has just listed.
+func (r *ResourceRegistry) setBaseline(resources []protocol.Resource) {
+	baseline := make(map[string]protocol.Resource, len(resources))
+	for _, res := range resources {
+		baseline[res.URI] = res
+	}
+
+	r.baselineMu.Lock()
+	r.baseline = baseline
+	r.baselineMu.Unlock()
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// RetryPolicy controls how a failed request to the client is retried.
+// The zero value makes a single attempt.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Values below 1 mean one attempt.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the second attempt.
+	// Defaults to 100ms.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between attempts. Zero means no cap.
+	MaxBackoff time.Duration
+
+	// Multiplier scales the delay after each attempt. Defaults to 2.
+	Multiplier float64
+}
+
+// errNoRetry marks an error that retrying cannot fix.
+type errNoRetry struct{ err error }
+
+func (e errNoRetry) Error() string { return e.err.Error() }
+func (e errNoRetry) Unwrap() error { return e.err }
+
+// permanent wraps err so retry returns it immediately.
+func permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return errNoRetry{err}
+}
+
+// retry calls fn until it succeeds, returns a permanent error, the attempts
+// run out, or ctx is done. The last error is returned unwrapped.
+func retry(ctx context.Context, policy RetryPolicy, fn func() error) error {
+	attempts := max(policy.MaxAttempts, 1)
+
+	backoff := policy.InitialBackoff
+	if backoff <= 0 {
+		backoff = 100 * time.Millisecond
+	}
+	multiplier := policy.Multiplier
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil {
+			return nil
+		}
+
+		var stop errNoRetry
+		if errors.As(err, &stop) {
+			return stop.err
+		}
+		if attempt >= attempts || ctx.Err() != nil {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		backoff = time.Duration(float64(backoff) * multiplier)
+		if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
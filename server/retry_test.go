@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryStopsAfterMaxAttempts(t *testing.T) {
+	calls := 0
+	errBusy := errors.New("busy")
+
+	err := retry(context.Background(), RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}, func() error {
+		calls++
+		return errBusy
+	})
+	if !errors.Is(err, errBusy) {
+		t.Fatalf("err = %v, want %v", err, errBusy)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
+
+func TestRetrySucceedsAfterTransientFailure(t *testing.T) {
+	calls := 0
+	err := retry(context.Background(), RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Millisecond}, func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("busy")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
+
+func TestRetryDoesNotRetryPermanentErrors(t *testing.T) {
+	calls := 0
+	err := retry(context.Background(), RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Millisecond}, func() error {
+		calls++
+		return permanent(ErrSamplingUnsupported)
+	})
+	if err != ErrSamplingUnsupported {
+		t.Fatalf("err = %v, want %v", err, ErrSamplingUnsupported)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestRetryStopsWhenContextIsDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := retry(ctx, RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Hour}, func() error {
+		calls++
+		cancel()
+		return errors.New("busy")
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
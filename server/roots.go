@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/amarbel-llc/go-lib-mcp/jsonrpc"
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+// ErrRootsUnsupported is returned by Roots when the client did not advertise
+// the roots capability.
+var ErrRootsUnsupported = errors.New("client does not support roots")
+
+// rootsCache holds the client's roots for a session until they change.
+type rootsCache struct {
+	mu    sync.Mutex
+	roots []protocol.Root
+	valid bool
+}
+
+// Roots returns the client's workspace roots. They are fetched with roots/list
+// on first use and cached until the client sends
+// notifications/roots/list_changed.
+func (s *Server) Roots(ctx context.Context) ([]protocol.Root, error) {
+	return s.session().listRoots(ctx)
+}
+
+func (sess *session) listRoots(ctx context.Context) ([]protocol.Root, error) {
+	sess.roots.mu.Lock()
+	if sess.roots.valid {
+		roots := sess.roots.roots
+		sess.roots.mu.Unlock()
+		return roots, nil
+	}
+	sess.roots.mu.Unlock()
+
+	return sess.fetchRoots(ctx)
+}
+
+func (sess *session) fetchRoots(ctx context.Context) ([]protocol.Root, error) {
+	client := sess.handler.client.Load()
+	if client == nil || client.Capabilities.Roots == nil {
+		return nil, ErrRootsUnsupported
+	}
+
+	raw, err := sess.request(ctx, protocol.MethodRootsList, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result protocol.RootsListResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("parsing roots/list result: %w", err)
+	}
+
+	sess.roots.mu.Lock()
+	sess.roots.roots = result.Roots
+	sess.roots.valid = true
+	sess.roots.mu.Unlock()
+
+	return result.Roots, nil
+}
+
+// handleRootsListChanged refreshes the cached roots, then passes the
+// notification on to Options.NotificationHandler. With Options.Sequential
+// the roots are only invalidated and fetched on the next Roots call: the
+// roots/list response could not be read while this handler blocks the loop.
+func (h *Handler) handleRootsListChanged(ctx context.Context, msg *jsonrpc.Message) {
+	if sess := h.sess; sess != nil {
+		sess.roots.mu.Lock()
+		sess.roots.valid = false
+		sess.roots.mu.Unlock()
+
+		if !h.server.opts.Sequential {
+			sess.fetchRoots(ctx)
+		}
+	}
+
+	h.dispatchNotification(ctx, msg)
+}
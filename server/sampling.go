@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/amarbel-llc/go-lib-mcp/jsonrpc"
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+// ErrSamplingUnsupported is returned by Sampling.CreateMessage when the client
+// did not advertise the sampling capability.
+var ErrSamplingUnsupported = errors.New("client does not support sampling")
+
+// Sampling requests LLM completions from the connected client.
+type Sampling struct {
+	server *Server
+}
+
+// SamplingOption customizes a single CreateMessage call.
+type SamplingOption func(*samplingConfig)
+
+type samplingConfig struct {
+	retry RetryPolicy
+}
+
+// WithRetry retries transient failures according to policy: too many pending
+// requests, a timed-out request, or a client error response with code
+// ServerNotInitialized or ContentModified. Anything else, such as a client
+// without sampling support or a user rejecting the request, is returned at
+// once.
+func WithRetry(policy RetryPolicy) SamplingOption {
+	return func(c *samplingConfig) {
+		c.retry = policy
+	}
+}
+
+// Sampling returns a helper for sampling/createMessage requests to the client.
+func (s *Server) Sampling() *Sampling {
+	return &Sampling{server: s}
+}
+
+// CreateMessage asks the client to generate a message from its LLM.
+// Invalid params.ModelPreferences fail with ErrInvalidParams before anything
+// is sent.
+func (sp *Sampling) CreateMessage(ctx context.Context, params protocol.CreateMessageParams, opts ...SamplingOption) (*protocol.CreateMessageResult, error) {
+	var cfg samplingConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if prefs := params.ModelPreferences; prefs != nil {
+		if err := prefs.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidParams, err)
+		}
+	}
+
+	sess := sp.server.session()
+	client := sess.handler.client.Load()
+	if client == nil || client.Capabilities.Sampling == nil {
+		return nil, ErrSamplingUnsupported
+	}
+
+	var raw json.RawMessage
+	err := retry(ctx, cfg.retry, func() error {
+		var err error
+		raw, err = sess.request(ctx, protocol.MethodSamplingCreateMessage, params)
+		if err != nil && !retryableRequestError(err) {
+			return permanent(err)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result protocol.CreateMessageResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("parsing sampling/createMessage result: %w", err)
+	}
+	return &result, nil
+}
+
+// retryableCodes are the JSON-RPC error codes for client conditions that may
+// clear up on their own. Any other error response, such as a user declining
+// the request, is final.
+var retryableCodes = []int{jsonrpc.ServerNotInitialized, jsonrpc.ContentModified}
+
+// retryableRequestError reports whether a failed request to the client might
+// succeed if sent again: too many requests were pending, the request timed
+// out, or the client answered with one of retryableCodes.
+func retryableRequestError(err error) bool {
+	if errors.Is(err, ErrTooManyPending) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var rpcErr *jsonrpc.Error
+	if errors.As(err, &rpcErr) {
+		return slices.Contains(retryableCodes, rpcErr.Code)
+	}
+
+	return false
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/amarbel-llc/go-lib-mcp/jsonrpc"
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+func TestModelPreferencesBuilder(t *testing.T) {
+	prefs, err := protocol.NewModelPreferences().
+		PreferModel("claude-3-5-sonnet").
+		Speed(0.8).
+		Intelligence(0.9).
+		Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	got, _ := json.Marshal(prefs)
+	want := `{"hints":[{"name":"claude-3-5-sonnet"}],"speedPriority":0.8,"intelligencePriority":0.9}`
+	if string(got) != want {
+		t.Errorf("preferences = %s, want %s", got, want)
+	}
+
+	if _, err := protocol.NewModelPreferences().Cost(1.5).Build(); err == nil {
+		t.Error("Build accepted a priority above 1")
+	}
+}
+
+func TestCreateMessageRejectsInvalidModelPreferences(t *testing.T) {
+	tr := newFakeTransport()
+	s, err := New(tr, Options{ServerName: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	initialize(t, s)
+
+	speed := -0.1
+	_, err = s.Sampling().CreateMessage(context.Background(), protocol.CreateMessageParams{
+		MaxTokens:        10,
+		ModelPreferences: &protocol.ModelPreferences{SpeedPriority: &speed},
+	})
+	if !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("err = %v, want ErrInvalidParams", err)
+	}
+	if n := tr.count(protocol.MethodSamplingCreateMessage); n != 0 {
+		t.Errorf("sent %d sampling requests, want 0", n)
+	}
+}
+
+// replyingTransport answers each request the server writes with reply.
+type replyingTransport struct {
+	*fakeTransport
+	reply   func(req *jsonrpc.Message) *jsonrpc.Message
+	deliver func(resp *jsonrpc.Message)
+}
+
+func (t *replyingTransport) Write(msg *jsonrpc.Message) error {
+	t.fakeTransport.Write(msg)
+	if msg.IsRequest() {
+		go t.deliver(t.reply(msg))
+	}
+	return nil
+}
+
+func samplingServer(t *testing.T, reply func(req *jsonrpc.Message) *jsonrpc.Message) (*Server, *replyingTransport) {
+	t.Helper()
+	tr := &replyingTransport{fakeTransport: newFakeTransport(), reply: reply}
+	s, err := New(tr, Options{ServerName: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr.deliver = s.session().outbound.deliver
+
+	h := s.session().handler
+	req, _ := jsonrpc.NewRequest(jsonrpc.NewNumberID(1), protocol.MethodInitialize, protocol.InitializeParams{
+		ProtocolVersion: protocol.ProtocolVersion,
+		Capabilities:    protocol.ClientCapabilities{Sampling: &protocol.SamplingCapability{}},
+	})
+	h.Handle(context.Background(), req)
+	initialized, _ := jsonrpc.NewNotification(protocol.MethodInitialized, nil)
+	h.Handle(context.Background(), initialized)
+	return s, tr
+}
+
+func TestCreateMessageDoesNotRetryRejection(t *testing.T) {
+	s, tr := samplingServer(t, func(req *jsonrpc.Message) *jsonrpc.Message {
+		resp, _ := jsonrpc.NewErrorResponse(req.RequestID(), -1, "user rejected sampling request", nil)
+		return resp
+	})
+
+	_, err := s.Sampling().CreateMessage(context.Background(), protocol.CreateMessageParams{MaxTokens: 10},
+		WithRetry(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}))
+	var rpcErr *jsonrpc.Error
+	if !errors.As(err, &rpcErr) || rpcErr.Code != -1 {
+		t.Fatalf("err = %v, want the client's rejection", err)
+	}
+	if n := tr.count(protocol.MethodSamplingCreateMessage); n != 1 {
+		t.Errorf("sent %d sampling requests, want 1", n)
+	}
+}
+
+func TestCreateMessageRetriesTransientErrors(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	s, tr := samplingServer(t, func(req *jsonrpc.Message) *jsonrpc.Message {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls == 1 {
+			resp, _ := jsonrpc.NewErrorResponse(req.RequestID(), jsonrpc.ContentModified, "busy", nil)
+			return resp
+		}
+		resp, _ := jsonrpc.NewResponse(req.RequestID(), protocol.CreateMessageResult{Role: "assistant"})
+		return resp
+	})
+
+	if _, err := s.Sampling().CreateMessage(context.Background(), protocol.CreateMessageParams{MaxTokens: 10},
+		WithRetry(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})); err != nil {
+		t.Fatalf("CreateMessage: %v", err)
+	}
+	if n := tr.count(protocol.MethodSamplingCreateMessage); n != 2 {
+		t.Errorf("sent %d sampling requests, want 2", n)
+	}
+}
@@ -2,102 +2,299 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"sync"
+	"time"
 
 	"github.com/amarbel-llc/go-lib-mcp/jsonrpc"
+	"github.com/amarbel-llc/go-lib-mcp/output"
 	"github.com/amarbel-llc/go-lib-mcp/transport"
 )
 
+// ErrAlreadyServing is returned by Run and Serve when the server is already
+// serving a connection.
+var ErrAlreadyServing = errors.New("server is already serving a connection")
+
+// ErrShutdownTimeout is returned by Run and Serve when in-flight handlers
+// did not finish within Options.ShutdownTimeout.
+var ErrShutdownTimeout = errors.New("shutdown timed out waiting for handlers")
+
 // Server is an MCP server that handles protocol messages.
 type Server struct {
-	transport transport.Transport
-	handler   *Handler
-	opts      Options
-	done      chan struct{}
-	wg        sync.WaitGroup
+	opts     Options
+	done     chan struct{}
+	wg       sync.WaitGroup
+	counters counters
+
+	// debouncers hold list changes back for Options.ListChangedDebounce;
+	// their pending notifications are dropped when a session ends.
+	debouncers []*debouncer
+
+	mu      sync.RWMutex
+	sess    *session
+	serving bool
+}
+
+// session is the state of a single client connection: the transport being
+// served, the handler's handshake state, and requests awaiting a response.
+// Each connection gets a fresh session so nothing leaks between clients.
+type session struct {
+	transport     transport.Transport
+	handler       *Handler
+	outbound      *outbound
+	roots         rootsCache
+	inflight      inflight
+	shutdown      *signal
+	ready         *signal
+	subscriptions subscriptions
 }
 
 // New creates a new MCP server with the given transport and options.
 func New(t transport.Transport, opts Options) (*Server, error) {
 	if opts.ServerName == "" {
-		return nil, fmt.Errorf("server name is required")
+		return nil, ErrServerNameRequired
+	}
+
+	if c := opts.Capabilities; c != nil {
+		if c.Tools != nil && opts.Tools == nil {
+			return nil, fmt.Errorf("tools capability advertised without a tool provider")
+		}
+		if c.Resources != nil && opts.Resources == nil {
+			return nil, fmt.Errorf("resources capability advertised without a resource provider")
+		}
+		if c.Resources != nil && c.Resources.Subscribe {
+			if _, ok := opts.Resources.(ResourceSubscriber); !ok {
+				return nil, fmt.Errorf("resources.subscribe advertised by a provider without subscription support")
+			}
+		}
+		if c.Prompts != nil && opts.Prompts == nil {
+			return nil, fmt.Errorf("prompts capability advertised without a prompt provider")
+		}
 	}
 
 	s := &Server{
-		transport: t,
-		opts:      opts,
-		done:      make(chan struct{}),
+		opts:     opts,
+		done:     make(chan struct{}),
+		counters: counters{started: time.Now()},
 	}
 
-	s.handler = NewHandler(s)
+	s.sess = s.newSession(t)
+	s.watchListChanges()
 	return s, nil
 }
 
+func (s *Server) newSession(t transport.Transport) *session {
+	sess := &session{
+		transport: t,
+		handler:   NewHandler(s),
+		outbound:  newOutbound(s.opts.MaxPendingRequests, s.opts.OutboundRequestTimeout),
+		shutdown:  newSignal(),
+		ready:     newSignal(),
+	}
+	sess.handler.sess = sess
+	return sess
+}
+
+// session returns the current connection's state.
+func (s *Server) session() *session {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sess
+}
+
 // Run starts the server and processes messages until the context is canceled
 // or the transport is closed.
 func (s *Server) Run(ctx context.Context) error {
+	return s.serve(ctx, s.session())
+}
+
+// Serve processes messages from t like Run, but as a new connection: the
+// client must initialize again, and handshake state, client info, and pending
+// requests from earlier connections are discarded. This lets a long-lived
+// Server accept successive connections, e.g. from a socket listener.
+// Connections are served one at a time.
+func (s *Server) Serve(ctx context.Context, t transport.Transport) error {
+	return s.serve(ctx, s.newSession(t))
+}
+
+func (s *Server) serve(ctx context.Context, sess *session) error {
+	s.mu.Lock()
+	if s.serving {
+		s.mu.Unlock()
+		return ErrAlreadyServing
+	}
+	s.serving = true
+	s.sess = sess
+	s.mu.Unlock()
+
+	defer func() {
+		for _, d := range s.debouncers {
+			d.stop()
+		}
+		s.mu.Lock()
+		s.serving = false
+		s.mu.Unlock()
+	}()
+
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	if p, ok := sess.transport.(PeerInfoProvider); ok {
+		ctx = context.WithValue(ctx, peerKey{}, p.PeerInfo())
+	}
+	ctx = context.WithValue(ctx, shutdownKey{}, sess.shutdown)
+
 	for {
 		select {
 		case <-ctx.Done():
-			s.gracefulShutdown()
+			s.gracefulShutdown(sess, cancel)
 			return ctx.Err()
 		case <-s.done:
-			s.gracefulShutdown()
-			return nil
+			return s.gracefulShutdown(sess, cancel)
 		default:
 		}
 
-		msg, err := s.transport.Read()
+		msg, err := sess.transport.Read()
+		var msgErr *transport.MessageError
+		if errors.As(err, &msgErr) {
+			// The bad message was skipped; the connection is still usable.
+			s.rejectMessage(sess, msgErr)
+			continue
+		}
 		if err != nil {
 			// EOF signals graceful shutdown from client
 			if err == io.EOF {
-				s.gracefulShutdown()
-				return nil
+				return s.gracefulShutdown(sess, cancel)
 			}
-			s.gracefulShutdown()
+			s.gracefulShutdown(sess, cancel)
 			return fmt.Errorf("reading message: %w", err)
 		}
 
+		// Responses answer our own requests to the client
+		if msg.IsResponse() {
+			sess.outbound.deliver(msg)
+			continue
+		}
+
+		if s.opts.Sequential {
+			s.handleMessage(ctx, sess, msg)
+			continue
+		}
+
 		// Process message concurrently
 		s.wg.Add(1)
 		go func() {
 			defer s.wg.Done()
-			s.handleMessage(ctx, msg)
+			s.handleMessage(ctx, sess, msg)
 		}()
 	}
 }
 
-func (s *Server) handleMessage(ctx context.Context, msg *jsonrpc.Message) {
-	resp, err := s.handler.Handle(ctx, msg)
+func (s *Server) handleMessage(ctx context.Context, sess *session, msg *jsonrpc.Message) {
+	if s.opts.RequestInterceptor != nil {
+		if msg = s.opts.RequestInterceptor(msg); msg == nil {
+			return
+		}
+	}
+
+	if msg.IsRequest() {
+		s.counters.requests.Add(1)
+		s.counters.inFlight.Add(1)
+		defer s.counters.inFlight.Add(-1)
+
+		var done func()
+		ctx, done = sess.inflight.track(ctx, msg.RequestID())
+		defer done()
+
+		if defaults := sess.handler.defaults.Load(); defaults != nil {
+			ctx = output.WithDefaults(ctx, *defaults)
+		}
+	}
+
+	resp, err := chain(sess.handler.Handle, s.opts.Middleware)(ctx, msg)
 	if err != nil {
 		// If there was an error and this is a request, send an error response
 		if msg.IsRequest() {
-			errResp, _ := jsonrpc.NewErrorResponse(*msg.ID, jsonrpc.InternalError, err.Error(), nil)
-			s.transport.Write(errResp)
+			s.counters.errors.Add(1)
+			errResp, _ := jsonrpc.NewErrorResponse(msg.RequestID(), jsonrpc.InternalError, err.Error(), nil)
+			s.writeResponse(sess, errResp)
 		}
 		return
 	}
 
+	if resp != nil && resp.Error != nil {
+		s.counters.errors.Add(1)
+	}
+
 	// Send response if there is one (requests get responses, notifications don't)
 	if resp != nil {
-		s.transport.Write(resp)
+		s.writeResponse(sess, resp)
+	}
+}
+
+// rejectMessage answers an unreadable message with a parse error if its ID
+// could be recovered, so the client is not left waiting.
+func (s *Server) rejectMessage(sess *session, msgErr *transport.MessageError) {
+	if msgErr.ID == nil {
+		return
+	}
+	s.counters.errors.Add(1)
+	resp, err := jsonrpc.NewErrorResponse(*msgErr.ID, jsonrpc.ParseError, msgErr.Error(), nil)
+	if err != nil {
+		return
 	}
+	s.writeResponse(sess, resp)
 }
 
-func (s *Server) gracefulShutdown() {
-	// Wait for all in-flight requests to complete
-	s.wg.Wait()
-	// Close the transport
-	s.transport.Close()
+func (s *Server) writeResponse(sess *session, resp *jsonrpc.Message) {
+	if s.opts.ResponseInterceptor != nil {
+		if resp = s.opts.ResponseInterceptor(resp); resp == nil {
+			return
+		}
+	}
+	sess.transport.Write(resp)
+}
+
+// gracefulShutdown drains in-flight handlers and closes the transport.
+// cancel cancels the context handlers run under, which ends the wait for
+// handlers that honor it once Options.ShutdownTimeout expires.
+func (s *Server) gracefulShutdown(sess *session, cancel context.CancelFunc) error {
+	// Let in-flight handlers wind down cooperatively
+	sess.shutdown.fire()
+	// Fail requests to the client that can no longer be answered
+	sess.outbound.close()
+	// Close the transport once handlers are done writing responses
+	defer sess.transport.Close()
+
+	drained := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(drained)
+	}()
+
+	var timeout <-chan time.Time
+	if s.opts.ShutdownTimeout > 0 {
+		timer := time.NewTimer(s.opts.ShutdownTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-drained:
+		return nil
+	case <-timeout:
+		// Abort handlers that honor their context; abandon the rest.
+		cancel()
+		return ErrShutdownTimeout
+	}
 }
 
 // Close signals the server to shut down gracefully.
-// This will cause Run() to return after all in-flight requests complete.
+// This will cause Run() to return after all in-flight requests complete, or
+// after Options.ShutdownTimeout; handlers can observe the shutdown with
+// ShuttingDownFromContext.
 func (s *Server) Close() {
 	close(s.done)
 }
@@ -0,0 +1,675 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amarbel-llc/go-lib-mcp/jsonrpc"
+	"github.com/amarbel-llc/go-lib-mcp/output"
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+func TestServeStartsFreshSession(t *testing.T) {
+	first := newFakeTransport()
+	s, err := New(first, Options{ServerName: "test"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	initialize(t, s)
+	close(first.in)
+	if err := s.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	second := newFakeTransport()
+	done := make(chan error, 1)
+	go func() { done <- s.Serve(context.Background(), second) }()
+
+	// Wait for Serve to install the new session before inspecting it.
+	for s.session().transport != second {
+		time.Sleep(time.Millisecond)
+	}
+
+	if s.session().handler.initialized.Load() {
+		t.Error("new connection should start uninitialized")
+	}
+	if s.session().handler.client.Load() != nil {
+		t.Error("new connection should not inherit client info")
+	}
+
+	close(second.in)
+	if err := <-done; err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+}
+
+func TestRequestWithNullIDIsRejected(t *testing.T) {
+	s, err := New(newFakeTransport(), Options{ServerName: "test", Tools: NewToolRegistry()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	initialize(t, s)
+
+	var msg jsonrpc.Message
+	if err := json.Unmarshal([]byte(`{"jsonrpc":"2.0","id":null,"method":"tools/list"}`), &msg); err != nil {
+		t.Fatal(err)
+	}
+	if msg.IsRequest() {
+		t.Fatal("message with null id reported as request")
+	}
+
+	resp, err := s.session().handler.Handle(context.Background(), &msg)
+	if err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+	if resp == nil || resp.Error == nil {
+		t.Fatalf("expected error response, got %+v", resp)
+	}
+	if resp.Error.Code != jsonrpc.InvalidRequest {
+		t.Errorf("error code = %d, want %d", resp.Error.Code, jsonrpc.InvalidRequest)
+	}
+	if !resp.RequestID().IsNull() {
+		t.Errorf("response id = %s, want null", resp.RequestID())
+	}
+}
+
+func TestNilToolResultBecomesEmptyResult(t *testing.T) {
+	tools := NewToolRegistry()
+	tools.Register("nothing", "Returns nil", json.RawMessage(`{"type":"object"}`),
+		func(ctx context.Context, args json.RawMessage) (*protocol.ToolCallResult, error) {
+			return nil, nil
+		})
+
+	s, err := New(newFakeTransport(), Options{ServerName: "test", Tools: tools})
+	if err != nil {
+		t.Fatal(err)
+	}
+	initialize(t, s)
+
+	req, _ := jsonrpc.NewRequest(jsonrpc.NewNumberID(2), protocol.MethodToolsCall, protocol.ToolCallParams{Name: "nothing"})
+	resp, err := s.session().handler.Handle(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("unexpected error response: %+v", resp.Error)
+	}
+	if got, want := string(resp.Result), `{"content":[]}`; got != want {
+		t.Errorf("result = %s, want %s", got, want)
+	}
+}
+
+func TestMaxContentBlocks(t *testing.T) {
+	tools := NewToolRegistry()
+	tools.Register("many", "Returns five blocks", json.RawMessage(`{"type":"object"}`),
+		func(ctx context.Context, args json.RawMessage) (*protocol.ToolCallResult, error) {
+			var result protocol.ToolCallResult
+			for i := range 5 {
+				result.Content = append(result.Content, protocol.TextContent(strconv.Itoa(i)))
+			}
+			return &result, nil
+		})
+
+	s, err := New(newFakeTransport(), Options{ServerName: "test", Tools: tools, MaxContentBlocks: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	initialize(t, s)
+
+	req, _ := jsonrpc.NewRequest(jsonrpc.NewNumberID(2), protocol.MethodToolsCall, protocol.ToolCallParams{Name: "many"})
+	resp, err := s.session().handler.Handle(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+
+	var result protocol.ToolCallResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Fatal(err)
+	}
+	var texts []string
+	for _, c := range result.Content {
+		texts = append(texts, c.Text)
+	}
+	if got, want := strings.Join(texts, "|"), "0|1|3 more blocks omitted"; got != want {
+		t.Errorf("content = %q, want %q", got, want)
+	}
+}
+
+func TestShutdownSignalsInFlightHandlers(t *testing.T) {
+	tr := newFakeTransport()
+	tools := NewToolRegistry()
+	started := make(chan struct{})
+	var sawShutdown, ctxValid bool
+	tools.Register("wait", "", nil, func(ctx context.Context, args json.RawMessage) (*protocol.ToolCallResult, error) {
+		if ShuttingDownFromContext(ctx) {
+			t.Error("shutting down before shutdown began")
+		}
+		close(started)
+		<-ShutdownFromContext(ctx)
+		sawShutdown = ShuttingDownFromContext(ctx)
+		ctxValid = ctx.Err() == nil
+		return &protocol.ToolCallResult{Content: []protocol.ContentBlock{protocol.TextContent("partial")}}, nil
+	})
+
+	s, err := New(tr, Options{ServerName: "test", Tools: tools})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	initialize(t, s)
+
+	req, _ := jsonrpc.NewRequest(jsonrpc.NewNumberID(2), protocol.MethodToolsCall, protocol.ToolCallParams{Name: "wait"})
+	tr.in <- req
+
+	done := make(chan error, 1)
+	go func() { done <- s.Run(context.Background()) }()
+
+	<-started
+	close(tr.in)
+	if err := <-done; err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if !sawShutdown {
+		t.Error("handler did not observe shutdown")
+	}
+	if !ctxValid {
+		t.Error("handler context was canceled by a graceful shutdown")
+	}
+	if n := len(tr.written); n != 1 || tr.written[0].Error != nil {
+		t.Fatalf("responses = %+v, want one successful response", tr.written)
+	}
+}
+
+func TestInflightDistinguishesNumberAndStringIDs(t *testing.T) {
+	var f inflight
+	numCtx, numDone := f.track(context.Background(), jsonrpc.NewNumberID(1))
+	strCtx, strDone := f.track(context.Background(), jsonrpc.NewStringID("1"))
+	defer strDone()
+
+	f.cancel(jsonrpc.NewNumberID(1), "")
+	if numCtx.Err() == nil {
+		t.Error("request 1 not cancelled")
+	}
+	if strCtx.Err() != nil {
+		t.Error(`cancelling request 1 cancelled request "1"`)
+	}
+
+	numDone()
+	f.cancel(jsonrpc.NewStringID("1"), "")
+	if strCtx.Err() == nil {
+		t.Error(`request "1" untracked when request 1 completed`)
+	}
+}
+
+func TestCancelledNotificationCarriesReason(t *testing.T) {
+	tr := newFakeTransport()
+	tools := NewToolRegistry()
+	started := make(chan struct{})
+	reason := make(chan string, 1)
+	tools.Register("wait", "", nil, func(ctx context.Context, args json.RawMessage) (*protocol.ToolCallResult, error) {
+		if got := CancellationReasonFromContext(ctx); got != "" {
+			t.Errorf("reason before cancellation = %q, want empty", got)
+		}
+		close(started)
+		<-ctx.Done()
+		reason <- CancellationReasonFromContext(ctx)
+		return nil, ctx.Err()
+	})
+
+	s, err := New(tr, Options{ServerName: "test", Tools: tools})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	initialize(t, s)
+
+	done := make(chan error, 1)
+	go func() { done <- s.Run(context.Background()) }()
+
+	req, _ := jsonrpc.NewRequest(jsonrpc.NewStringID("call-1"), protocol.MethodToolsCall, protocol.ToolCallParams{Name: "wait"})
+	tr.in <- req
+	<-started
+
+	cancel, _ := jsonrpc.NewNotification(protocol.MethodCancelled, protocol.CancelledNotification{
+		RequestID: json.RawMessage(`"call-1"`),
+		Reason:    "user aborted",
+	})
+	tr.in <- cancel
+
+	if got := <-reason; got != "user aborted" {
+		t.Fatalf("reason = %q, want %q", got, "user aborted")
+	}
+	close(tr.in)
+	if err := <-done; err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+}
+
+func TestOutputDefaultsPerClient(t *testing.T) {
+	tr := newFakeTransport()
+	tools := NewToolRegistry()
+	got := make(chan output.Defaults, 1)
+	tools.Register("limits", "", nil, func(ctx context.Context, args json.RawMessage) (*protocol.ToolCallResult, error) {
+		got <- output.DefaultsFromContext(ctx)
+		return nil, nil
+	})
+
+	small := output.Defaults{MaxBytes: 4096, MaxLines: 100, MaxItems: 10}
+	s, err := New(tr, Options{
+		ServerName: "test",
+		Tools:      tools,
+		Sequential: true,
+		OutputDefaults: func(client protocol.InitializeParams) output.Defaults {
+			if client.ClientInfo.Name == "small-client" {
+				return small
+			}
+			return output.StandardDefaults()
+		},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	req, _ := jsonrpc.NewRequest(jsonrpc.NewNumberID(1), protocol.MethodInitialize, protocol.InitializeParams{
+		ProtocolVersion: protocol.ProtocolVersion,
+		ClientInfo:      protocol.Implementation{Name: "small-client"},
+	})
+	tr.in <- req
+	call, _ := jsonrpc.NewRequest(jsonrpc.NewNumberID(2), protocol.MethodToolsCall, protocol.ToolCallParams{Name: "limits"})
+	tr.in <- call
+	close(tr.in)
+	if err := s.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if d := <-got; d != small {
+		t.Fatalf("defaults = %+v, want %+v", d, small)
+	}
+}
+
+func TestSequentialRootsListChangedDoesNotBlock(t *testing.T) {
+	tr := newFakeTransport()
+	s, err := New(tr, Options{ServerName: "test", Sequential: true})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	req, _ := jsonrpc.NewRequest(jsonrpc.NewNumberID(1), protocol.MethodInitialize, protocol.InitializeParams{
+		ProtocolVersion: protocol.ProtocolVersion,
+		Capabilities:    protocol.ClientCapabilities{Roots: &protocol.RootsCapability{ListChanged: true}},
+	})
+	tr.in <- req
+	initialized, _ := jsonrpc.NewNotification(protocol.MethodInitialized, nil)
+	tr.in <- initialized
+	changed, _ := jsonrpc.NewNotification(protocol.MethodRootsListChanged, nil)
+	tr.in <- changed
+	ping, _ := jsonrpc.NewRequest(jsonrpc.NewNumberID(2), protocol.MethodPing, nil)
+	tr.in <- ping
+	close(tr.in)
+
+	done := make(chan error, 1)
+	go func() { done <- s.Run(context.Background()) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run blocked on notifications/roots/list_changed")
+	}
+
+	if n := tr.count(protocol.MethodRootsList); n != 0 {
+		t.Errorf("sent %d roots/list requests on the read loop, want 0", n)
+	}
+}
+
+func TestShutdownTimeoutAbandonsStuckHandlers(t *testing.T) {
+	tr := newFakeTransport()
+	tools := NewToolRegistry()
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	defer close(release)
+	canceled := make(chan struct{})
+	tools.Register("stuck", "", nil, func(ctx context.Context, args json.RawMessage) (*protocol.ToolCallResult, error) {
+		started <- struct{}{}
+		<-release // ignores its context
+		return nil, nil
+	})
+	tools.Register("polite", "", nil, func(ctx context.Context, args json.RawMessage) (*protocol.ToolCallResult, error) {
+		started <- struct{}{}
+		<-ctx.Done()
+		close(canceled)
+		return nil, ctx.Err()
+	})
+
+	s, err := New(tr, Options{ServerName: "test", Tools: tools, ShutdownTimeout: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	initialize(t, s)
+
+	for i, name := range []string{"stuck", "polite"} {
+		req, _ := jsonrpc.NewRequest(jsonrpc.NewNumberID(int64(i+2)), protocol.MethodToolsCall, protocol.ToolCallParams{Name: name})
+		tr.in <- req
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- s.Run(context.Background()) }()
+	<-started
+	<-started
+	close(tr.in)
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrShutdownTimeout) {
+			t.Fatalf("Run = %v, want ErrShutdownTimeout", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after the shutdown timeout")
+	}
+
+	select {
+	case <-canceled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("context-aware handler was not canceled")
+	}
+}
+
+func TestUnknownClientCapabilities(t *testing.T) {
+	initMsg, _ := jsonrpc.NewRequest(jsonrpc.NewNumberID(1), protocol.MethodInitialize, json.RawMessage(
+		`{"protocolVersion":"`+protocol.ProtocolVersion+`","capabilities":{"logging":{},"teleport":{},"experimental":{"x":{}}}}`))
+
+	strict, err := New(newFakeTransport(), Options{ServerName: "test", StrictCapabilities: true})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	resp, err := strict.session().handler.Handle(context.Background(), initMsg)
+	if err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+	if resp.Error == nil || resp.Error.Code != jsonrpc.InvalidParams {
+		t.Fatalf("strict initialize = %+v, want InvalidParams", resp)
+	}
+
+	tr := newFakeTransport()
+	lenient, err := New(tr, Options{ServerName: "test", WarnOnUnknownCapabilities: true})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	resp, _ = lenient.session().handler.Handle(context.Background(), initMsg)
+	if resp.Error != nil {
+		t.Fatalf("lenient initialize failed: %v", resp.Error)
+	}
+	initialized, _ := jsonrpc.NewNotification(protocol.MethodInitialized, nil)
+	lenient.session().handler.Handle(context.Background(), initialized)
+
+	if n := tr.count(protocol.MethodLoggingMessage); n != 1 {
+		t.Fatalf("warnings = %d, want 1", n)
+	}
+	if !strings.Contains(string(tr.written[0].Params), "teleport") {
+		t.Fatalf("warning %s does not name the capability", tr.written[0].Params)
+	}
+}
+
+func TestPingIncludesBuildInfo(t *testing.T) {
+	s, err := New(newFakeTransport(), Options{
+		ServerName: "test",
+		BuildInfo:  &protocol.BuildInfo{Version: "v1.2.3", Commit: "abc123"},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	req, _ := jsonrpc.NewRequest(jsonrpc.NewNumberID(1), protocol.MethodPing, protocol.PingParams{Meta: json.RawMessage(`{"trace":"t1"}`)})
+	resp, err := s.session().handler.Handle(context.Background(), req)
+	if err != nil {
+		t.Fatalf("ping: %v", err)
+	}
+
+	var result struct {
+		Meta struct {
+			Trace     string             `json:"trace"`
+			BuildInfo protocol.BuildInfo `json:"buildInfo"`
+		} `json:"_meta"`
+	}
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if result.Meta.Trace != "t1" {
+		t.Errorf("trace = %q, want the request _meta echoed", result.Meta.Trace)
+	}
+	if result.Meta.BuildInfo.Version != "v1.2.3" || result.Meta.BuildInfo.Commit != "abc123" {
+		t.Errorf("buildInfo = %+v", result.Meta.BuildInfo)
+	}
+}
+
+func TestDynamicPromptArguments(t *testing.T) {
+	models := []string{"small"}
+	var argsErr error
+	prompts := NewPromptRegistry()
+	prompts.RegisterDynamic("ask", "Ask a model",
+		func(ctx context.Context) ([]protocol.PromptArgument, error) {
+			return []protocol.PromptArgument{{Name: "model", Enum: models}}, argsErr
+		},
+		func(ctx context.Context, args map[string]string) (*protocol.PromptGetResult, error) {
+			return &protocol.PromptGetResult{}, nil
+		})
+
+	s, err := New(newFakeTransport(), Options{ServerName: "test", Prompts: prompts})
+	if err != nil {
+		t.Fatal(err)
+	}
+	initialize(t, s)
+	h := s.session().handler
+
+	list := func() *jsonrpc.Message {
+		req, _ := jsonrpc.NewRequest(jsonrpc.NewNumberID(2), protocol.MethodPromptsList, nil)
+		resp, err := h.Handle(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Handle: %v", err)
+		}
+		return resp
+	}
+
+	models = append(models, "large")
+	var result protocol.PromptsListResult
+	if err := json.Unmarshal(list().Result, &result); err != nil {
+		t.Fatal(err)
+	}
+	if got := result.Prompts[0].Arguments[0].Enum; !slices.Equal(got, []string{"small", "large"}) {
+		t.Errorf("enum = %v, want current models", got)
+	}
+
+	if _, err := prompts.GetPrompt(context.Background(), "ask", map[string]string{"model": "large"}); err != nil {
+		t.Errorf("GetPrompt with current model: %v", err)
+	}
+
+	argsErr = errors.New("models unavailable")
+	if resp := list(); resp.Error == nil || resp.Error.Code != jsonrpc.InternalError {
+		t.Errorf("error = %+v, want internal error", resp.Error)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	a, b := NewToolRegistry(), NewToolRegistry()
+	a.Register("echo", "", json.RawMessage(`{"type":"object"}`), noopTool)
+	b.Register("echo", "", json.RawMessage(`{"type":"object"}`), noopTool)
+	b.Register("bad", "", json.RawMessage(`{"type":"string"}`), noopTool)
+	tools := ToolProvider(&dupTools{a, b})
+
+	prompts := NewPromptRegistry()
+	prompts.Register(protocol.Prompt{
+		Name:      "review",
+		Arguments: []protocol.PromptArgument{{Name: "file"}, {Name: "file"}},
+	}, nil)
+
+	s, err := New(newFakeTransport(), Options{
+		ServerName: "test",
+		Tools:      tools,
+		Resources:  NewResourceRegistry(),
+		Prompts:    prompts,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = s.Validate(context.Background())
+	for _, want := range []string{
+		"duplicate tool echo",
+		"tool bad: input schema type is string, want object",
+		"resource provider has no resources or templates",
+		"prompt review: duplicate argument file",
+	} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() = %v, want error containing %q", err, want)
+		}
+	}
+
+	ok, err := New(newFakeTransport(), Options{ServerName: "test", Tools: a})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ok.Validate(context.Background()); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+}
+
+// dupTools lists the tools of two registries without deduplicating them.
+type dupTools struct{ a, b *ToolRegistry }
+
+func (d *dupTools) ListTools(ctx context.Context) ([]protocol.Tool, error) {
+	return append(d.a.Tools(), d.b.Tools()...), nil
+}
+
+func (d *dupTools) CallTool(ctx context.Context, name string, args json.RawMessage) (*protocol.ToolCallResult, error) {
+	return d.a.CallTool(ctx, name, args)
+}
+
+func TestResourceInfo(t *testing.T) {
+	reg := NewResourceRegistry()
+	reg.RegisterResource(protocol.Resource{URI: "file:///a.txt", Name: "a", MimeType: "text/plain", Size: 3}, nil)
+	if err := reg.RegisterTemplate(protocol.ResourceTemplate{URITemplate: "file:///{name}"}, func(ctx context.Context, uri string) (*protocol.ResourceReadResult, error) {
+		return &protocol.ResourceReadResult{}, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	info, ok := reg.ResourceInfo("file:///a.txt")
+	if !ok || info.Name != "a" || info.MimeType != "text/plain" || info.Size != 3 {
+		t.Errorf("ResourceInfo = %+v, %v", info, ok)
+	}
+	if _, ok := reg.ResourceInfo("file:///b.txt"); ok {
+		t.Error("ResourceInfo reported a templated URI")
+	}
+}
+
+func TestToolCallDoesNotMutateReturnedResult(t *testing.T) {
+	shared := &protocol.ToolCallResult{}
+	many := &protocol.ToolCallResult{Content: []protocol.ContentBlock{
+		protocol.TextContent("a"), protocol.TextContent("b"), protocol.TextContent("c"),
+	}}
+	tools := NewToolRegistry()
+	tools.Register("shared", "", nil, func(ctx context.Context, args json.RawMessage) (*protocol.ToolCallResult, error) {
+		return shared, nil
+	})
+	tools.Register("many", "", nil, func(ctx context.Context, args json.RawMessage) (*protocol.ToolCallResult, error) {
+		return many, nil
+	})
+
+	s, err := New(newFakeTransport(), Options{ServerName: "test", Tools: tools, MaxContentBlocks: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	initialize(t, s)
+
+	for i, name := range []string{"shared", "many"} {
+		req, _ := jsonrpc.NewRequest(jsonrpc.NewNumberID(int64(i+2)), protocol.MethodToolsCall, protocol.ToolCallParams{Name: name})
+		if _, err := s.session().handler.Handle(context.Background(), req); err != nil {
+			t.Fatalf("Handle: %v", err)
+		}
+	}
+	if shared.Content != nil {
+		t.Errorf("shared result content = %+v, want nil", shared.Content)
+	}
+	if len(many.Content) != 3 {
+		t.Errorf("shared result content = %+v, want 3 blocks", many.Content)
+	}
+}
+
+// hiddenTools lists only its registry's tools but can also call "hidden".
+type hiddenTools struct{ *ToolRegistry }
+
+func (h hiddenTools) CallTool(ctx context.Context, name string, args json.RawMessage) (*protocol.ToolCallResult, error) {
+	if name == "hidden" {
+		return &protocol.ToolCallResult{Content: []protocol.ContentBlock{protocol.TextContent("ran")}}, nil
+	}
+	return h.ToolRegistry.CallTool(ctx, name, args)
+}
+
+func TestReadOnlyDeniesUnlistedTools(t *testing.T) {
+	tools := NewToolRegistry()
+	tools.Register("read", "", nil, noopTool, ReadOnly())
+	tools.Register("write", "", nil, noopTool)
+
+	s, err := New(newFakeTransport(), Options{ServerName: "test", Tools: hiddenTools{tools}, ReadOnly: true})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	initialize(t, s)
+
+	for i, tt := range []struct {
+		name    string
+		allowed bool
+	}{
+		{"read", true},
+		{"write", false},
+		{"hidden", false},
+	} {
+		req, _ := jsonrpc.NewRequest(jsonrpc.NewNumberID(int64(i+2)), protocol.MethodToolsCall, protocol.ToolCallParams{Name: tt.name})
+		resp, err := s.session().handler.Handle(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Handle: %v", err)
+		}
+		var result protocol.ToolCallResult
+		if err := json.Unmarshal(resp.Result, &result); err != nil {
+			t.Fatalf("%s: %v (error %v)", tt.name, err, resp.Error)
+		}
+		if result.IsError == tt.allowed {
+			t.Errorf("%s: isError = %v, want allowed = %v", tt.name, result.IsError, tt.allowed)
+		}
+	}
+}
+
+func TestUnknownPromptIsInvalidParams(t *testing.T) {
+	prompts := NewPromptRegistry()
+
+	_, err := prompts.GetPrompt(context.Background(), "missing", nil)
+	if !errors.Is(err, ErrUnknownPrompt) {
+		t.Fatalf("GetPrompt err = %v, want ErrUnknownPrompt", err)
+	}
+	_, err = PromptsAsResources(prompts, "prompt://").ReadResource(context.Background(), "prompt://missing")
+	if !errors.Is(err, ErrUnknownResource) {
+		t.Fatalf("ReadResource err = %v, want ErrUnknownResource", err)
+	}
+
+	s, err := New(newFakeTransport(), Options{ServerName: "test", Prompts: prompts})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	initialize(t, s)
+
+	req, _ := jsonrpc.NewRequest(jsonrpc.NewNumberID(2), protocol.MethodPromptsGet, protocol.PromptGetParams{Name: "missing"})
+	resp, err := s.session().handler.Handle(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+	if resp.Error == nil || resp.Error.Code != jsonrpc.InvalidParams {
+		t.Fatalf("error = %+v, want InvalidParams", resp.Error)
+	}
+}
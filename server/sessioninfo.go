@@ -0,0 +1,35 @@
+package server
+
+import "github.com/amarbel-llc/go-lib-mcp/protocol"
+
+// SessionInfo describes what was negotiated with the connected client.
+type SessionInfo struct {
+	// ClientInfo is the client's name and version.
+	ClientInfo protocol.Implementation
+
+	// ClientCapabilities is what the client said it supports.
+	ClientCapabilities protocol.ClientCapabilities
+
+	// ServerCapabilities is what the server advertised to the client.
+	ServerCapabilities protocol.ServerCapabilities
+
+	// ProtocolVersion is the negotiated protocol version.
+	ProtocolVersion string
+}
+
+// Session returns what was negotiated with the current connection's client.
+// It reports false until the client has sent initialize.
+func (s *Server) Session() (SessionInfo, bool) {
+	h := s.session().handler
+	client := h.client.Load()
+	if client == nil {
+		return SessionInfo{}, false
+	}
+
+	return SessionInfo{
+		ClientInfo:         client.ClientInfo,
+		ClientCapabilities: client.Capabilities,
+		ServerCapabilities: h.clientCapabilities(),
+		ProtocolVersion:    protocol.ProtocolVersion,
+	}, true
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"context"
+	"sync"
+)
+
+type shutdownKey struct{}
+
+// signal is a one-shot event, such as a connection beginning to shut down
+// or the client finishing initialization.
+type signal struct {
+	once sync.Once
+	ch   chan struct{}
+}
+
+func newSignal() *signal {
+	return &signal{ch: make(chan struct{})}
+}
+
+func (s *signal) fire() {
+	s.once.Do(func() { close(s.ch) })
+}
+
+// ShutdownFromContext returns a channel that is closed when the connection
+// the current request arrived on begins shutting down: the client
+// disconnected, Close was called, or Run's context was canceled. It returns
+// nil, which blocks forever, outside of a request.
+//
+// Unlike context cancellation this is a cooperative signal: the handler's
+// context stays valid and the server waits for the handler to return before
+// closing the transport, so a handler can flush partial results and decline
+// new sub-work. If Options.ShutdownTimeout is set, handlers still running
+// when it expires have their context canceled and their responses may be
+// lost, so long-running handlers should watch this channel and finish
+// promptly.
+func ShutdownFromContext(ctx context.Context) <-chan struct{} {
+	if s, ok := ctx.Value(shutdownKey{}).(*signal); ok {
+		return s.ch
+	}
+	return nil
+}
+
+// ShuttingDownFromContext reports whether the connection the current request
+// arrived on has begun shutting down. See ShutdownFromContext.
+func ShuttingDownFromContext(ctx context.Context) bool {
+	select {
+	case <-ShutdownFromContext(ctx):
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/amarbel-llc/go-lib-mcp/output"
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+// SpillOptions configures SpillToResource.
+type SpillOptions struct {
+	// Threshold is the text size in bytes above which a result is spilled.
+	// Defaults to output.StandardDefaults().MaxBytes.
+	Threshold int
+
+	// PreviewBytes bounds the preview kept inline. Defaults to 2000.
+	PreviewBytes int
+
+	// TTL is how long the full output stays readable. Defaults to 10 minutes.
+	TTL time.Duration
+}
+
+// SpillToResource wraps a tool handler so that results whose text exceeds
+// opts.Threshold are stored in resources as a temporary resource instead of
+// being returned inline. The caller receives a note giving the URI of the
+// full output, which is unregistered after opts.TTL, and an embedded resource
+// carrying a truncated preview under that URI with a "#preview" fragment.
+// Multiple text blocks are joined with newlines. Results containing non-text
+// content are returned unchanged.
+func SpillToResource(resources *ResourceRegistry, opts SpillOptions, handler ToolHandler) ToolHandler {
+	if opts.Threshold <= 0 {
+		opts.Threshold = output.StandardDefaults().MaxBytes
+	}
+	if opts.PreviewBytes <= 0 {
+		opts.PreviewBytes = 2000
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = 10 * time.Minute
+	}
+
+	return func(ctx context.Context, args json.RawMessage) (*protocol.ToolCallResult, error) {
+		result, err := handler(ctx, args)
+		if err != nil || result == nil {
+			return result, err
+		}
+
+		text, ok := resultText(result)
+		if !ok || len(text) <= opts.Threshold {
+			return result, nil
+		}
+
+		uri, err := spillURI()
+		if err != nil {
+			return nil, err
+		}
+
+		resources.RegisterResource(protocol.Resource{
+			URI:         uri,
+			Name:        "Full tool output",
+			Description: fmt.Sprintf("%d bytes, available for %s", len(text), opts.TTL),
+			MimeType:    "text/plain",
+		}, func(ctx context.Context, uri string) (*protocol.ResourceReadResult, error) {
+			return &protocol.ResourceReadResult{
+				Contents: []protocol.ResourceContent{{URI: uri, MimeType: "text/plain", Text: text}},
+			}, nil
+		})
+		time.AfterFunc(opts.TTL, func() { resources.Unregister(uri) })
+
+		preview := output.LimitText(text, output.TextLimits{MaxBytes: opts.PreviewBytes})
+
+		spilled := *result
+		spilled.Content = []protocol.ContentBlock{
+			protocol.TextContent(fmt.Sprintf("[output truncated: preview shows %d of %d bytes; full output at %s]",
+				len(preview.Content), len(text), uri)),
+			protocol.ResourceBlock(protocol.ResourceContent{URI: uri + "#preview", MimeType: "text/plain", Text: preview.Content}),
+		}
+		return &spilled, nil
+	}
+}
+
+// resultText joins the result's text blocks with newlines, reporting false
+// if it has any other kind of content.
+func resultText(result *protocol.ToolCallResult) (string, bool) {
+	texts := make([]string, len(result.Content))
+	for i, block := range result.Content {
+		if block.Type != "text" {
+			return "", false
+		}
+		texts[i] = block.Text
+	}
+	return strings.Join(texts, "\n"), true
+}
+
+func spillURI() (string, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("generating resource URI: %w", err)
+	}
+	return "mcp://results/" + hex.EncodeToString(b[:]), nil
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+func TestSpillToResource(t *testing.T) {
+	resources := NewResourceRegistry()
+	blocks := []string{strings.Repeat("a", 60), strings.Repeat("b", 60)}
+	handler := SpillToResource(resources, SpillOptions{Threshold: 100, PreviewBytes: 20, TTL: 50 * time.Millisecond},
+		func(ctx context.Context, args json.RawMessage) (*protocol.ToolCallResult, error) {
+			var result protocol.ToolCallResult
+			for _, text := range blocks {
+				result.Content = append(result.Content, protocol.TextContent(text))
+			}
+			return &result, nil
+		})
+	ctx := context.Background()
+
+	result, err := handler(ctx, nil)
+	if err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+	if len(result.Content) != 2 || result.Content[1].Type != "resource" {
+		t.Fatalf("content = %+v, want note and resource", result.Content)
+	}
+	embedded := result.Content[1].Resource
+	if err := embedded.Validate(); err != nil {
+		t.Errorf("embedded resource: %v", err)
+	}
+	if len(embedded.Text) > 20 || !strings.HasPrefix(blocks[0], embedded.Text) {
+		t.Errorf("preview = %q, want at most 20 bytes of the output", embedded.Text)
+	}
+	uri, ok := strings.CutSuffix(embedded.URI, "#preview")
+	if !ok {
+		t.Fatalf("preview URI = %s, want the full output's URI with a #preview fragment", embedded.URI)
+	}
+	if note := result.Content[0].Text; !strings.Contains(note, uri) || strings.Contains(note, embedded.Text) {
+		t.Errorf("note = %q, want the full output's URI and no repeated preview", note)
+	}
+
+	full, err := resources.ReadResource(ctx, uri)
+	if err != nil {
+		t.Fatalf("ReadResource: %v", err)
+	}
+	if got, want := full.Contents[0].Text, blocks[0]+"\n"+blocks[1]; got != want {
+		t.Errorf("full output = %q, want %q", got, want)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for resources.Has(uri) {
+		if time.Now().After(deadline) {
+			t.Fatal("spilled resource not unregistered after TTL")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	blocks = []string{"short"}
+	result, _ = handler(ctx, nil)
+	if len(result.Content) != 1 || result.Content[0].Text != "short" {
+		t.Errorf("result under threshold = %+v, want unchanged", result.Content)
+	}
+}
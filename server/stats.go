@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+// StatsURI is the URI of the resource returned by Server.StatsResource.
+const StatsURI = "mcp://server/stats"
+
+// Stats is a snapshot of the server's request counters.
+type Stats struct {
+	// Requests is the number of requests received.
+	Requests int64 `json:"requests"`
+
+	// Errors is the number of requests answered with a JSON-RPC error.
+	Errors int64 `json:"errors"`
+
+	// InFlight is the number of requests currently being handled.
+	InFlight int64 `json:"inFlight"`
+
+	// UptimeSeconds is the time since the server was created.
+	UptimeSeconds float64 `json:"uptimeSeconds"`
+}
+
+// counters tracks request statistics for a server.
+type counters struct {
+	started  time.Time
+	requests atomic.Int64
+	errors   atomic.Int64
+	inFlight atomic.Int64
+}
+
+// Stats returns a snapshot of the server's request counters.
+func (s *Server) Stats() Stats {
+	return Stats{
+		Requests:      s.counters.requests.Load(),
+		Errors:        s.counters.errors.Load(),
+		InFlight:      s.counters.inFlight.Load(),
+		UptimeSeconds: time.Since(s.counters.started).Seconds(),
+	}
+}
+
+// StatsResource returns a resource exposing Stats as JSON at StatsURI,
+// ready to pass to ResourceRegistry.RegisterResource:
+//
+//	resources.RegisterResource(srv.StatsResource())
+func (s *Server) StatsResource() (protocol.Resource, ResourceReader) {
+	resource := protocol.Resource{
+		URI:         StatsURI,
+		Name:        "Server Stats",
+		Description: "Request counters and uptime for this server",
+		MimeType:    "application/json",
+	}
+
+	reader := func(ctx context.Context, uri string) (*protocol.ResourceReadResult, error) {
+		data, err := json.MarshalIndent(s.Stats(), "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return &protocol.ResourceReadResult{
+			Contents: []protocol.ResourceContent{
+				{URI: uri, MimeType: "application/json", Text: string(data)},
+			},
+		}, nil
+	}
+
+	return resource, reader
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+// StreamingToolHandler handles a tool invocation that produces output
+// incrementally. Each block sent on out is relayed to the client as a
+// notifications/progress notification carrying the block in
+// _meta.partialContent. The handler must stop sending once it returns and
+// should stop early when ctx is done.
+//
+// Streamed blocks are only delivered to clients that supplied a progress
+// token; other clients receive nothing but the final result. The returned
+// result should therefore stand on its own, e.g. by repeating the full output
+// or summarizing it.
+type StreamingToolHandler func(ctx context.Context, args json.RawMessage, out chan<- protocol.ContentBlock) (*protocol.ToolCallResult, error)
+
+// RegisterStreaming adds a tool whose handler streams partial output.
+// Like Register, it fails with ErrDuplicateTool if the name is taken.
+func (r *ToolRegistry) RegisterStreaming(name, description string, schema json.RawMessage, handler StreamingToolHandler, opts ...ToolOption) error {
+	return r.Register(name, description, schema, streamingHandler(handler), opts...)
+}
+
+func streamingHandler(handler StreamingToolHandler) ToolHandler {
+	return func(ctx context.Context, args json.RawMessage) (*protocol.ToolCallResult, error) {
+		progress, hasProgress := ProgressFromContext(ctx)
+
+		out := make(chan protocol.ContentBlock)
+		relayed := make(chan struct{})
+
+		go func() {
+			defer close(relayed)
+			n := 0
+			for block := range out {
+				// Keep draining after cancellation so the handler never blocks.
+				if !hasProgress || ctx.Err() != nil {
+					continue
+				}
+				n++
+				progress.Send(protocol.ProgressNotification{
+					Progress: float64(n),
+					Meta:     &protocol.ProgressMeta{PartialContent: []protocol.ContentBlock{block}},
+				})
+			}
+		}()
+
+		result, err := handler(ctx, args, out)
+		close(out)
+		<-relayed
+
+		return result, err
+	}
+}
+
+// NDJSONToolHandler handles a tool invocation whose output is a stream of
+// newline-delimited JSON values, such as per-test results from a test runner.
+// If the returned reader is an io.Closer, it is closed once consumed.
+type NDJSONToolHandler func(ctx context.Context, args json.RawMessage) (io.Reader, error)
+
+// RegisterNDJSON adds a tool whose handler returns NDJSON output.
+//
+// For clients that supplied a progress token, each value is relayed as it is
+// read in a notifications/progress notification carrying it in
+// _meta.structuredContent, and the final result only reports how many values
+// were streamed, as {"streamed": n}. Other clients get a single result once
+// the stream ends, with every value in structuredContent as {"items": [...]}
+// and the values as NDJSON text. A malformed value fails the call.
+// Like Register, it fails with ErrDuplicateTool if the name is taken.
+func (r *ToolRegistry) RegisterNDJSON(name, description string, schema json.RawMessage, handler NDJSONToolHandler, opts ...ToolOption) error {
+	return r.Register(name, description, schema, ndjsonHandler(handler), opts...)
+}
+
+func ndjsonHandler(handler NDJSONToolHandler) ToolHandler {
+	return func(ctx context.Context, args json.RawMessage) (*protocol.ToolCallResult, error) {
+		stream, err := handler(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+		if c, ok := stream.(io.Closer); ok {
+			defer c.Close()
+		}
+
+		progress, hasProgress := ProgressFromContext(ctx)
+
+		var items []json.RawMessage
+		n := 0
+		dec := json.NewDecoder(stream)
+		for {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			var item json.RawMessage
+			if err := dec.Decode(&item); err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("reading NDJSON value %d: %w", n+1, err)
+			}
+			n++
+
+			if !hasProgress {
+				items = append(items, item)
+				continue
+			}
+			progress.Send(protocol.ProgressNotification{
+				Progress: float64(n),
+				Meta:     &protocol.ProgressMeta{StructuredContent: item},
+			})
+		}
+
+		if hasProgress {
+			return &protocol.ToolCallResult{
+				Content:           []protocol.ContentBlock{protocol.TextContent(fmt.Sprintf("streamed %d results", n))},
+				StructuredContent: map[string]any{"streamed": n},
+			}, nil
+		}
+
+		var text strings.Builder
+		for _, item := range items {
+			text.Write(item)
+			text.WriteByte('\n')
+		}
+		if items == nil {
+			items = []json.RawMessage{}
+		}
+		return &protocol.ToolCallResult{
+			Content:           []protocol.ContentBlock{protocol.TextContent(text.String())},
+			StructuredContent: map[string]any{"items": items},
+		}, nil
+	}
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+const testResults = `{"test":"TestA","ok":true}
+{"test":"TestB","ok":false}
+`
+
+func ndjsonTools(t *testing.T) *ToolRegistry {
+	t.Helper()
+	tools := NewToolRegistry()
+	err := tools.RegisterNDJSON("test", "", nil, func(ctx context.Context, args json.RawMessage) (io.Reader, error) {
+		return strings.NewReader(testResults), nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterNDJSON: %v", err)
+	}
+	return tools
+}
+
+func TestNDJSONStreamsValuesAsProgress(t *testing.T) {
+	var sent []protocol.ProgressNotification
+	ctx := withProgress(context.Background(), &protocol.RequestMeta{ProgressToken: json.RawMessage(`1`)},
+		func(method string, params any) error {
+			sent = append(sent, params.(protocol.ProgressNotification))
+			return nil
+		})
+
+	result, err := ndjsonTools(t).CallTool(ctx, "test", nil)
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+
+	if len(sent) != 2 {
+		t.Fatalf("progress notifications = %d, want 2", len(sent))
+	}
+	if got := string(sent[1].Meta.StructuredContent); got != `{"test":"TestB","ok":false}` {
+		t.Errorf("second value = %s", got)
+	}
+	if got := result.StructuredContent.(map[string]any)["streamed"]; got != 2 {
+		t.Errorf("streamed = %v, want 2", got)
+	}
+}
+
+func TestNDJSONBuffersWithoutProgressToken(t *testing.T) {
+	result, err := ndjsonTools(t).CallTool(context.Background(), "test", nil)
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+
+	items := result.StructuredContent.(map[string]any)["items"].([]json.RawMessage)
+	if len(items) != 2 {
+		t.Fatalf("items = %d, want 2", len(items))
+	}
+	if result.Content[0].Text != testResults {
+		t.Errorf("text = %q, want the NDJSON values", result.Content[0].Text)
+	}
+}
+
+func TestNDJSONMalformedValueFails(t *testing.T) {
+	tools := NewToolRegistry()
+	tools.RegisterNDJSON("bad", "", nil, func(ctx context.Context, args json.RawMessage) (io.Reader, error) {
+		return strings.NewReader("{\"ok\":true}\n{not json}\n"), nil
+	})
+
+	if _, err := tools.CallTool(context.Background(), "bad", nil); err == nil || !strings.Contains(err.Error(), "value 2") {
+		t.Fatalf("err = %v, want an error naming value 2", err)
+	}
+}
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/amarbel-llc/go-lib-mcp/jsonrpc"
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+// SubscriptionMode controls which URIs a ResourceRegistry accepts in
+// resources/subscribe.
+type SubscriptionMode string
+
+const (
+	// SubscribeExact accepts only the URIs of registered static resources.
+	SubscribeExact SubscriptionMode = "exact"
+
+	// SubscribePrefix also accepts a prefix of a registered URI, such as
+	// "file:///project/", which covers every resource under it.
+	SubscribePrefix SubscriptionMode = "prefix"
+
+	// SubscribeAll accepts any URI, e.g. ones matching a resource template.
+	SubscribeAll SubscriptionMode = "all"
+)
+
+// ResourceSubscriber is implemented by resource providers that support
+// resources/subscribe. The server tracks each client's subscriptions;
+// Subscribe decides which URIs are acceptable and SubscriptionMode how
+// subscriptions match updates. Unless Options.Capabilities is set, the
+// subscribe capability is advertised when SubscriptionMode is not empty.
+type ResourceSubscriber interface {
+	// Subscribe validates a subscription request. An error wrapping
+	// ErrInvalidParams is reported to the client as InvalidParams.
+	Subscribe(ctx context.Context, uri string) error
+
+	// Unsubscribe is called when the client cancels a subscription.
+	Unsubscribe(ctx context.Context, uri string) error
+
+	// SubscriptionMode reports the granularity of subscriptions, or "" if
+	// subscriptions are not enabled.
+	SubscriptionMode() SubscriptionMode
+}
+
+// SetSubscriptionMode enables resources/subscribe and sets which URIs it
+// accepts. Subscriptions are off until it is called.
+func (r *ResourceRegistry) SetSubscriptionMode(mode SubscriptionMode) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subscriptionMode = mode
+}
+
+// SubscriptionMode implements ResourceSubscriber.
+func (r *ResourceRegistry) SubscriptionMode() SubscriptionMode {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.subscriptionMode
+}
+
+// Subscribe implements ResourceSubscriber, accepting uri if the
+// subscription mode allows it. Without a mode, as when subscribe is
+// advertised through Options.Capabilities, SubscribeExact applies.
+func (r *ResourceRegistry) Subscribe(ctx context.Context, uri string) error {
+	mode := r.SubscriptionMode()
+	if mode == "" {
+		mode = SubscribeExact
+	}
+	if mode == SubscribeAll {
+		return nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, res := range r.resources {
+		if res.URI == uri || mode == SubscribePrefix && strings.HasPrefix(res.URI, uri) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: cannot subscribe to %s in %s mode: %w", ErrInvalidParams, uri, mode, ErrUnknownResource)
+}
+
+// Unsubscribe implements ResourceSubscriber.
+func (r *ResourceRegistry) Unsubscribe(ctx context.Context, uri string) error {
+	return nil
+}
+
+// subscriptions is the set of URIs a client subscribed to.
+type subscriptions struct {
+	mu   sync.Mutex
+	uris map[string]bool
+}
+
+func (s *subscriptions) add(uri string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.uris == nil {
+		s.uris = make(map[string]bool)
+	}
+	s.uris[uri] = true
+}
+
+func (s *subscriptions) remove(uri string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.uris, uri)
+}
+
+// covers reports whether an update to uri concerns any subscription.
+func (s *subscriptions) covers(uri string, mode SubscriptionMode) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.uris[uri] {
+		return true
+	}
+	if mode == SubscribePrefix {
+		for sub := range s.uris {
+			if strings.HasPrefix(uri, sub) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// NotifyResourceUpdated sends notifications/resources/updated for uri if
+// the client subscribed to it, or in SubscribePrefix mode to a prefix of it.
+func (s *Server) NotifyResourceUpdated(uri string) error {
+	sub, ok := s.opts.Resources.(ResourceSubscriber)
+	if !ok {
+		return nil
+	}
+	if !s.session().subscriptions.covers(uri, sub.SubscriptionMode()) {
+		return nil
+	}
+	return s.Notify(protocol.MethodResourcesUpdated, protocol.ResourceUpdatedNotification{URI: uri})
+}
+
+func (h *Handler) handleResourcesSubscribe(ctx context.Context, msg *jsonrpc.Message) (*jsonrpc.Message, error) {
+	// Every ResourceRegistry is a ResourceSubscriber; only honor requests
+	// when subscriptions were opted into and advertised.
+	sub, ok := h.server.opts.Resources.(ResourceSubscriber)
+	if caps := h.clientCapabilities(); !ok || caps.Resources == nil || !caps.Resources.Subscribe {
+		return jsonrpc.NewErrorResponse(msg.RequestID(), jsonrpc.MethodNotFound, "resource subscriptions not supported", nil)
+	}
+
+	var params protocol.ResourceSubscribeParams
+	if err := json.Unmarshal(msg.Params, &params); err != nil || params.URI == "" {
+		return jsonrpc.NewErrorResponse(msg.RequestID(), jsonrpc.InvalidParams, "invalid params", nil)
+	}
+
+	if msg.Method == protocol.MethodResourcesSubscribe {
+		if err := sub.Subscribe(ctx, params.URI); err != nil {
+			return jsonrpc.NewErrorResponse(msg.RequestID(), errorCode(err), err.Error(), nil)
+		}
+		if h.sess != nil {
+			h.sess.subscriptions.add(params.URI)
+		}
+	} else {
+		if err := sub.Unsubscribe(ctx, params.URI); err != nil {
+			return jsonrpc.NewErrorResponse(msg.RequestID(), errorCode(err), err.Error(), nil)
+		}
+		if h.sess != nil {
+			h.sess.subscriptions.remove(params.URI)
+		}
+	}
+
+	return jsonrpc.NewResponse(msg.RequestID(), struct{}{})
+}
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/amarbel-llc/go-lib-mcp/jsonrpc"
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+func subscribe(t *testing.T, s *Server, id int64, uri string) *jsonrpc.Message {
+	t.Helper()
+	req, _ := jsonrpc.NewRequest(jsonrpc.NewNumberID(id), protocol.MethodResourcesSubscribe, protocol.ResourceSubscribeParams{URI: uri})
+	resp, err := s.session().handler.Handle(context.Background(), req)
+	if err != nil {
+		t.Fatalf("subscribe %s: %v", uri, err)
+	}
+	return resp
+}
+
+func TestSubscriptionModes(t *testing.T) {
+	resources := NewResourceRegistry()
+	resources.RegisterResource(protocol.Resource{URI: "file:///project/main.go"}, nil)
+
+	tests := []struct {
+		mode SubscriptionMode
+		uri  string
+		ok   bool
+	}{
+		{SubscribeExact, "file:///project/main.go", true},
+		{SubscribeExact, "file:///project/", false},
+		{SubscribePrefix, "file:///project/", true},
+		{SubscribePrefix, "file:///other/", false},
+		{SubscribeAll, "doc://anything", true},
+	}
+	for _, tt := range tests {
+		resources.SetSubscriptionMode(tt.mode)
+		s, err := New(newFakeTransport(), Options{ServerName: "test", Resources: resources})
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		initialize(t, s)
+
+		resp := subscribe(t, s, 2, tt.uri)
+		if ok := resp.Error == nil; ok != tt.ok {
+			t.Errorf("%s mode, subscribe %s: error = %v, want ok = %v", tt.mode, tt.uri, resp.Error, tt.ok)
+		}
+		if !tt.ok && resp.Error != nil && resp.Error.Code != jsonrpc.InvalidParams {
+			t.Errorf("%s mode, subscribe %s: code = %d, want InvalidParams", tt.mode, tt.uri, resp.Error.Code)
+		}
+	}
+}
+
+func TestSubscribeRequiresOptIn(t *testing.T) {
+	resources := NewResourceRegistry()
+	resources.RegisterResource(protocol.Resource{URI: "file:///project/main.go"}, nil)
+
+	s, err := New(newFakeTransport(), Options{ServerName: "test", Resources: resources})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	initialize(t, s)
+	if resp := subscribe(t, s, 2, "file:///project/main.go"); resp.Error == nil || resp.Error.Code != jsonrpc.MethodNotFound {
+		t.Errorf("subscribe without opt-in: error = %v, want MethodNotFound", resp.Error)
+	}
+
+	s, err = New(newFakeTransport(), Options{
+		ServerName:   "test",
+		Resources:    resources,
+		Capabilities: &protocol.ServerCapabilities{Resources: &protocol.ResourcesCapability{Subscribe: true}},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	initialize(t, s)
+	if resp := subscribe(t, s, 2, "file:///project/main.go"); resp.Error != nil {
+		t.Errorf("subscribe with advertised capability: %v", resp.Error)
+	}
+
+	// A filter that hides subscriptions from this client also refuses them.
+	s, err = New(newFakeTransport(), Options{
+		ServerName:   "test",
+		Resources:    resources,
+		Capabilities: &protocol.ServerCapabilities{Resources: &protocol.ResourcesCapability{Subscribe: true}},
+		CapabilityFilter: func(_ protocol.InitializeParams, caps protocol.ServerCapabilities) protocol.ServerCapabilities {
+			caps.Resources = &protocol.ResourcesCapability{}
+			return caps
+		},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	initialize(t, s)
+	if resp := subscribe(t, s, 2, "file:///project/main.go"); resp.Error == nil || resp.Error.Code != jsonrpc.MethodNotFound {
+		t.Errorf("subscribe filtered out: error = %v, want MethodNotFound", resp.Error)
+	}
+}
+
+func TestNotifyResourceUpdatedOnlyForSubscriptions(t *testing.T) {
+	tr := newFakeTransport()
+	resources := NewResourceRegistry()
+	resources.RegisterResource(protocol.Resource{URI: "file:///project/main.go"}, nil)
+	resources.RegisterResource(protocol.Resource{URI: "file:///notes.txt"}, nil)
+	resources.SetSubscriptionMode(SubscribePrefix)
+
+	s, err := New(tr, Options{ServerName: "test", Resources: resources})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	req, _ := jsonrpc.NewRequest(jsonrpc.NewNumberID(1), protocol.MethodInitialize, protocol.InitializeParams{
+		ProtocolVersion: protocol.ProtocolVersion,
+	})
+	resp, _ := s.session().handler.Handle(context.Background(), req)
+	var init protocol.InitializeResult
+	if err := json.Unmarshal(resp.Result, &init); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !init.Capabilities.Resources.Subscribe {
+		t.Fatal("subscribe capability not advertised")
+	}
+	if _, ok := init.Capabilities.Experimental[protocol.ExperimentalSubscriptionMode]; !ok {
+		t.Fatal("subscription mode not advertised")
+	}
+
+	if resp := subscribe(t, s, 2, "file:///project/"); resp.Error != nil {
+		t.Fatalf("subscribe: %v", resp.Error)
+	}
+	s.NotifyResourceUpdated("file:///project/main.go")
+	s.NotifyResourceUpdated("file:///notes.txt")
+
+	if n := tr.count(protocol.MethodResourcesUpdated); n != 1 {
+		t.Fatalf("updated notifications = %d, want 1", n)
+	}
+}
@@ -0,0 +1,51 @@
+package server
+
+// closestName returns the candidate with the smallest edit distance to name,
+// if that distance is small enough to plausibly be a typo.
+func closestName(name string, candidates []string) (string, bool) {
+	best := ""
+	bestDist := -1
+
+	for _, c := range candidates {
+		d := levenshtein(name, c)
+		if bestDist < 0 || d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+
+	if bestDist < 0 || bestDist > suggestionThreshold(name) {
+		return "", false
+	}
+	return best, true
+}
+
+// suggestionThreshold allows roughly one edit per three characters, with a
+// floor of two so short names still get suggestions for simple typos.
+func suggestionThreshold(name string) int {
+	return max(2, len([]rune(name))/3)
+}
+
+// levenshtein computes the edit distance between a and b over runes.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"get_time", "get_tim", 1},
+		{"héllo", "hello", 1},
+	}
+
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestEnableSuggestions(t *testing.T) {
+	tools := NewToolRegistry()
+	tools.Register("get_current_time", "", nil, noopTool)
+	tools.Register("echo", "", nil, noopTool)
+	tools.EnableSuggestions()
+
+	result, err := tools.CallTool(context.Background(), "get_curent_time", nil)
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected error result")
+	}
+	if want := "did you mean get_current_time?"; !strings.Contains(result.Content[0].Text, want) {
+		t.Errorf("text = %q, want it to contain %q", result.Content[0].Text, want)
+	}
+
+	result, _ = tools.CallTool(context.Background(), "delete_everything", nil)
+	if strings.Contains(result.Content[0].Text, "did you mean") {
+		t.Errorf("unexpected suggestion for distant name: %q", result.Content[0].Text)
+	}
+}
+
+func TestFilteredToolProviderNotFound(t *testing.T) {
+	tools := NewToolRegistry()
+	tools.Register("git_status", "", nil, noopTool)
+	tools.Register("git_push", "", nil, noopTool)
+	tools.EnableSuggestions()
+	p := FilteredToolProvider(tools, nil, []string{"git_push"})
+	ctx := context.Background()
+
+	result, _ := p.CallTool(ctx, "git_pus", nil)
+	if text := result.Content[0].Text; strings.Contains(text, "git_push") {
+		t.Errorf("suggestion names a denied tool: %q", text)
+	}
+
+	result, _ = p.CallTool(ctx, "git_push", nil)
+	if !result.IsError || result.Content[0].Text != "unknown tool: git_push" {
+		t.Errorf("denied tool result = %+v, want unknown tool", result)
+	}
+
+	tools.SetNotFoundHandler(func(name string) *protocol.ToolCallResult {
+		return protocol.ErrorResult("no such tool " + name)
+	})
+	result, _ = p.CallTool(ctx, "git_push", nil)
+	if got := result.Content[0].Text; got != "no such tool git_push" {
+		t.Errorf("denied tool result = %q, want the not-found handler's", got)
+	}
+}
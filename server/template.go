@@ -0,0 +1,227 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+// TemplateOptions configures TemplatePrompts.
+type TemplateOptions struct {
+	// MissingArgError makes GetPrompt fail when a placeholder's argument is not
+	// provided, and marks every argument as required. By default missing
+	// arguments render as empty strings.
+	MissingArgError bool
+}
+
+// templatePrompts serves text/template files as prompts.
+type templatePrompts struct {
+	prompts   []protocol.Prompt
+	templates map[string]*template.Template
+}
+
+// TemplatePrompts loads every *.tmpl file in dir as a prompt. The prompt name
+// is the file name without its extension, and each {{.name}} placeholder
+// becomes a PromptArgument. GetPrompt renders the template against the
+// provided arguments into a single user message.
+func TemplatePrompts(dir string, opts TemplateOptions) (PromptProvider, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		return nil, err
+	}
+
+	p := &templatePrompts{templates: make(map[string]*template.Template)}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading prompt template: %w", err)
+		}
+
+		name := strings.TrimSuffix(filepath.Base(path), ".tmpl")
+
+		tmpl, err := parsePromptTemplate(name, string(data), opts.MissingArgError)
+		if err != nil {
+			return nil, err
+		}
+
+		var args []protocol.PromptArgument
+		for _, field := range templateFields(tmpl) {
+			args = append(args, protocol.PromptArgument{
+				Name:     field,
+				Required: opts.MissingArgError,
+			})
+		}
+
+		p.prompts = append(p.prompts, protocol.Prompt{Name: name, Arguments: args})
+		p.templates[name] = tmpl
+	}
+
+	return p, nil
+}
+
+// ListPrompts implements PromptProvider.
+func (p *templatePrompts) ListPrompts(ctx context.Context) ([]protocol.Prompt, error) {
+	return p.prompts, nil
+}
+
+// GetPrompt implements PromptProvider.
+func (p *templatePrompts) GetPrompt(ctx context.Context, name string, args map[string]string) (*protocol.PromptGetResult, error) {
+	tmpl, ok := p.templates[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownPrompt, name)
+	}
+
+	text, err := renderTemplate(tmpl, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &protocol.PromptGetResult{
+		Messages: []protocol.PromptMessage{
+			{Role: "user", Content: protocol.TextContent(text)},
+		},
+	}, nil
+}
+
+func parsePromptTemplate(name, text string, missingArgError bool) (*template.Template, error) {
+	missingKey := "missingkey=zero"
+	if missingArgError {
+		missingKey = "missingkey=error"
+	}
+
+	tmpl, err := template.New(name).Option(missingKey).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parsing prompt template %s: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+func renderTemplate(tmpl *template.Template, args map[string]string) (string, error) {
+	if args == nil {
+		args = map[string]string{}
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, args); err != nil {
+		return "", fmt.Errorf("rendering prompt %s: %w", tmpl.Name(), err)
+	}
+	return sb.String(), nil
+}
+
+// templateFields returns the top-level field names ({{.name}}) referenced by
+// tmpl, in order of first appearance. Fields referenced inside range or with
+// blocks are relative to a different dot and are not included.
+func templateFields(tmpl *template.Template) []string {
+	var fields []string
+	seen := make(map[string]bool)
+
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			fields = append(fields, name)
+		}
+	}
+
+	var walkPipe func(*parse.PipeNode)
+	var walk func(parse.Node)
+
+	walkPipe = func(pipe *parse.PipeNode) {
+		if pipe == nil {
+			return
+		}
+		for _, cmd := range pipe.Cmds {
+			for _, arg := range cmd.Args {
+				switch n := arg.(type) {
+				case *parse.FieldNode:
+					add(n.Ident[0])
+				case *parse.PipeNode:
+					walkPipe(n)
+				}
+			}
+		}
+	}
+
+	walk = func(node parse.Node) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, child := range n.Nodes {
+				walk(child)
+			}
+		case *parse.ActionNode:
+			walkPipe(n.Pipe)
+		case *parse.IfNode:
+			walkPipe(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.RangeNode:
+			walkPipe(n.Pipe)
+			walk(n.ElseList)
+		case *parse.WithNode:
+			walkPipe(n.Pipe)
+			walk(n.ElseList)
+		}
+	}
+
+	if tmpl.Tree != nil {
+		walk(tmpl.Tree.Root)
+	}
+	return fields
+}
+
+// TemplatePrompt returns a renderer for prompt that executes tmpl, a
+// text/template, against the prompt's arguments and returns the output as a
+// single user message. Conditionals and loops are available as usual;
+// declared arguments that were not provided render as empty strings, while
+// a missing Required argument fails with an error wrapping ErrInvalidParams.
+//
+// The template is parsed here, and it is an error for it to reference a
+// top-level field ({{.name}}) that is not one of the prompt's arguments.
+func TemplatePrompt(prompt protocol.Prompt, tmpl string) (PromptRenderer, error) {
+	t, err := parsePromptTemplate(prompt.Name, tmpl, false)
+	if err != nil {
+		return nil, err
+	}
+
+	declared := make(map[string]bool, len(prompt.Arguments))
+	for _, arg := range prompt.Arguments {
+		declared[arg.Name] = true
+	}
+	for _, field := range templateFields(t) {
+		if !declared[field] {
+			return nil, fmt.Errorf("prompt template %s: undeclared argument %s", prompt.Name, field)
+		}
+	}
+
+	return func(ctx context.Context, args map[string]string) (*protocol.PromptGetResult, error) {
+		values := make(map[string]string, len(prompt.Arguments))
+		for _, arg := range prompt.Arguments {
+			value, ok := args[arg.Name]
+			if !ok && arg.Required {
+				return nil, fmt.Errorf("%w: missing required argument %s", ErrInvalidParams, arg.Name)
+			}
+			values[arg.Name] = value
+		}
+
+		text, err := renderTemplate(t, values)
+		if err != nil {
+			return nil, err
+		}
+
+		return &protocol.PromptGetResult{
+			Description: prompt.Description,
+			Messages: []protocol.PromptMessage{
+				{Role: "user", Content: protocol.TextContent(text)},
+			},
+		}, nil
+	}, nil
+}
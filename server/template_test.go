@@ -0,0 +1,64 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTemplatePrompts(t *testing.T) {
+	dir := t.TempDir()
+	tmpl := "Review this {{.language}} code{{if .focus}} focusing on {{.focus}}{{end}}:\n{{.code}}"
+	if err := os.WriteFile(filepath.Join(dir, "review.tmpl"), []byte(tmpl), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := TemplatePrompts(dir, TemplateOptions{})
+	if err != nil {
+		t.Fatalf("TemplatePrompts: %v", err)
+	}
+
+	prompts, _ := p.ListPrompts(context.Background())
+	if len(prompts) != 1 || prompts[0].Name != "review" {
+		t.Fatalf("prompts = %+v, want one prompt named review", prompts)
+	}
+
+	var names []string
+	for _, arg := range prompts[0].Arguments {
+		names = append(names, arg.Name)
+	}
+	if got, want := len(names), 3; got != want {
+		t.Fatalf("arguments = %v, want language, focus, code", names)
+	}
+
+	result, err := p.GetPrompt(context.Background(), "review", map[string]string{
+		"language": "Go",
+		"code":     "x := 1",
+	})
+	if err != nil {
+		t.Fatalf("GetPrompt: %v", err)
+	}
+	if got, want := result.Messages[0].Content.Text, "Review this Go code:\nx := 1"; got != want {
+		t.Errorf("text = %q, want %q", got, want)
+	}
+}
+
+func TestTemplatePromptsMissingArgError(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "greet.tmpl"), []byte("Hello {{.name}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := TemplatePrompts(dir, TemplateOptions{MissingArgError: true})
+	if err != nil {
+		t.Fatalf("TemplatePrompts: %v", err)
+	}
+
+	if _, err := p.GetPrompt(context.Background(), "greet", nil); err == nil {
+		t.Fatal("expected error for missing argument")
+	}
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+type templateVarsKey struct{}
+
+// TemplateVarsFromContext returns the variables extracted from the requested
+// URI when a resource template's reader is called. Readers of static
+// resources report false.
+func TemplateVarsFromContext(ctx context.Context) (map[string]string, bool) {
+	vars, ok := ctx.Value(templateVarsKey{}).(map[string]string)
+	return vars, ok
+}
+
+// uriTemplate matches URIs against an RFC 6570 template. Only simple
+// expressions ({var}, which match within a path segment and are
+// percent-decoded) and reserved expressions ({+var}, which match anything
+// and are passed through) with a single variable each are supported.
+type uriTemplate struct {
+	pattern *regexp.Regexp
+	names   []string
+	raw     []bool
+}
+
+var templateVarName = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+func parseURITemplate(template string) (*uriTemplate, error) {
+	t := &uriTemplate{}
+	var pattern strings.Builder
+	pattern.WriteString("^")
+
+	rest := template
+	for {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			break
+		}
+		end := strings.IndexByte(rest[open:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("uri template %q: unclosed expression", template)
+		}
+		pattern.WriteString(regexp.QuoteMeta(rest[:open]))
+
+		expr := rest[open+1 : open+end]
+		raw := strings.HasPrefix(expr, "+")
+		name := strings.TrimPrefix(expr, "+")
+		if !templateVarName.MatchString(name) {
+			return nil, fmt.Errorf("uri template %q: unsupported expression {%s}", template, expr)
+		}
+		if raw {
+			pattern.WriteString("(.+)")
+		} else {
+			pattern.WriteString("([^/?#]+)")
+		}
+		t.names = append(t.names, name)
+		t.raw = append(t.raw, raw)
+
+		rest = rest[open+end+1:]
+	}
+	pattern.WriteString(regexp.QuoteMeta(rest))
+	pattern.WriteString("$")
+
+	re, err := regexp.Compile(pattern.String())
+	if err != nil {
+		return nil, fmt.Errorf("uri template %q: %w", template, err)
+	}
+	t.pattern = re
+	return t, nil
+}
+
+// match reports whether uri matches the template and returns its variables.
+func (t *uriTemplate) match(uri string) (map[string]string, bool) {
+	m := t.pattern.FindStringSubmatch(uri)
+	if m == nil {
+		return nil, false
+	}
+
+	vars := make(map[string]string, len(t.names))
+	for i, name := range t.names {
+		value := m[i+1]
+		if !t.raw[i] {
+			unescaped, err := url.PathUnescape(value)
+			if err != nil {
+				return nil, false
+			}
+			value = unescaped
+		}
+		vars[name] = value
+	}
+	return vars, true
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/amarbel-llc/go-lib-mcp/protocol"
+)
+
+func TestRegisterTemplateReadsMatchingURIs(t *testing.T) {
+	r := NewResourceRegistry()
+	err := r.RegisterTemplate(protocol.ResourceTemplate{URITemplate: "doc://{id}/rev/{+path}", Name: "doc"},
+		func(ctx context.Context, uri string) (*protocol.ResourceReadResult, error) {
+			vars, ok := TemplateVarsFromContext(ctx)
+			if !ok {
+				t.Error("template vars missing from context")
+			}
+			return &protocol.ResourceReadResult{
+				Contents: []protocol.ResourceContent{{URI: uri, Text: vars["id"] + ":" + vars["path"]}},
+			}, nil
+		})
+	if err != nil {
+		t.Fatalf("RegisterTemplate: %v", err)
+	}
+
+	result, err := r.ReadResource(context.Background(), "doc://a%20b/rev/x/y.txt")
+	if err != nil {
+		t.Fatalf("ReadResource: %v", err)
+	}
+	if got := result.Contents[0].Text; got != "a b:x/y.txt" {
+		t.Fatalf("text = %q, want %q", got, "a b:x/y.txt")
+	}
+
+	if _, err := r.ReadResource(context.Background(), "doc://a/b/rev/x"); !errors.Is(err, ErrUnknownResource) {
+		t.Fatalf("err = %v, want ErrUnknownResource", err)
+	}
+}
+
+func TestRegisterTemplateRejectsUnsupportedExpressions(t *testing.T) {
+	r := NewResourceRegistry()
+	reader := func(ctx context.Context, uri string) (*protocol.ResourceReadResult, error) { return nil, nil }
+
+	for _, tmpl := range []string{"doc://{a,b}", "doc://{?q}", "doc://{id"} {
+		if err := r.RegisterTemplate(protocol.ResourceTemplate{URITemplate: tmpl}, reader); err == nil {
+			t.Errorf("RegisterTemplate(%q) succeeded, want error", tmpl)
+		}
+	}
+	if len(r.Templates()) != 0 {
+		t.Fatalf("templates = %d, want 0", len(r.Templates()))
+	}
+}
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Validate checks the server's providers for misconfigurations that would
+// otherwise only surface as failures once a client connects: providers
+// with nothing to offer, duplicate tool, resource, or prompt names (e.g.
+// across registries merged by a Mux or MultiProvider), tools without an
+// object input schema or with examples that do not fit it, and prompts
+// with duplicate argument names. It returns all problems found, joined.
+//
+// Validate is opt-in; New does not call it. Call it after registering
+// everything and before Run.
+func (s *Server) Validate(ctx context.Context) error {
+	var errs []error
+
+	if s.opts.Tools != nil {
+		errs = append(errs, validateTools(ctx, s.opts.Tools)...)
+	}
+	if s.opts.Resources != nil {
+		errs = append(errs, validateResources(ctx, s.opts.Resources)...)
+	}
+	if s.opts.Prompts != nil {
+		errs = append(errs, validatePrompts(ctx, s.opts.Prompts)...)
+	}
+
+	return errors.Join(errs...)
+}
+
+func validateTools(ctx context.Context, p ToolProvider) []error {
+	tools, err := p.ListTools(ctx)
+	if err != nil {
+		return []error{fmt.Errorf("listing tools: %w", err)}
+	}
+	if len(tools) == 0 {
+		return []error{errors.New("tool provider has no tools")}
+	}
+
+	var errs []error
+	seen := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		if seen[tool.Name] {
+			errs = append(errs, fmt.Errorf("duplicate tool %s", tool.Name))
+		}
+		seen[tool.Name] = true
+
+		if err := validateInputSchema(tool.InputSchema); err != nil {
+			errs = append(errs, fmt.Errorf("tool %s: %w", tool.Name, err))
+		}
+	}
+	return errs
+}
+
+// validateInputSchema checks that a tool's input schema is a JSON object
+// schema whose examples fit it.
+func validateInputSchema(inputSchema json.RawMessage) error {
+	if len(inputSchema) == 0 {
+		return errors.New("input schema is missing")
+	}
+
+	var schema struct {
+		Type any `json:"type"`
+	}
+	if err := json.Unmarshal(inputSchema, &schema); err != nil {
+		return fmt.Errorf("input schema is not a JSON object: %w", err)
+	}
+	if schema.Type != "object" {
+		return fmt.Errorf("input schema type is %v, want object", schema.Type)
+	}
+
+	return validateExamples(inputSchema)
+}
+
+func validateResources(ctx context.Context, p ResourceProvider) []error {
+	resources, err := p.ListResources(ctx)
+	if err != nil {
+		return []error{fmt.Errorf("listing resources: %w", err)}
+	}
+	templates, err := p.ListResourceTemplates(ctx)
+	if err != nil {
+		return []error{fmt.Errorf("listing resource templates: %w", err)}
+	}
+	if len(resources) == 0 && len(templates) == 0 {
+		return []error{errors.New("resource provider has no resources or templates")}
+	}
+
+	var errs []error
+	seen := make(map[string]bool, len(resources))
+	for _, r := range resources {
+		if seen[r.URI] {
+			errs = append(errs, fmt.Errorf("duplicate resource %s", r.URI))
+		}
+		seen[r.URI] = true
+	}
+	seenTemplates := make(map[string]bool, len(templates))
+	for _, t := range templates {
+		if seenTemplates[t.URITemplate] {
+			errs = append(errs, fmt.Errorf("duplicate resource template %s", t.URITemplate))
+		}
+		seenTemplates[t.URITemplate] = true
+	}
+	return errs
+}
+
+func validatePrompts(ctx context.Context, p PromptProvider) []error {
+	prompts, err := p.ListPrompts(ctx)
+	if err != nil {
+		return []error{fmt.Errorf("listing prompts: %w", err)}
+	}
+	if len(prompts) == 0 {
+		return []error{errors.New("prompt provider has no prompts")}
+	}
+
+	var errs []error
+	seen := make(map[string]bool, len(prompts))
+	for _, prompt := range prompts {
+		if seen[prompt.Name] {
+			errs = append(errs, fmt.Errorf("duplicate prompt %s", prompt.Name))
+		}
+		seen[prompt.Name] = true
+
+		args := make(map[string]bool, len(prompt.Arguments))
+		for _, arg := range prompt.Arguments {
+			if args[arg.Name] {
+				errs = append(errs, fmt.Errorf("prompt %s: duplicate argument %s", prompt.Name, arg.Name))
+			}
+			args[arg.Name] = true
+		}
+	}
+	return errs
+}
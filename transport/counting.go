@@ -0,0 +1,88 @@
+package transport
+
+import (
+	"encoding/json"
+	"sync/atomic"
+
+	"github.com/amarbel-llc/go-lib-mcp/jsonrpc"
+)
+
+// ByteCounter is implemented by transports that count the bytes they
+// exchange on the wire, framing included, such as Stdio.
+type ByteCounter interface {
+	BytesRead() int64
+	BytesWritten() int64
+}
+
+// Counting wraps a Transport and counts the bytes of messages read and
+// written through it, e.g. for quota tracking.
+//
+// If inner implements ByteCounter, as Stdio does, its wire counts are
+// reported: they are exact, including whitespace, framing, and messages
+// that failed to parse, as well as anything inner exchanged before it was
+// wrapped. Otherwise each message is measured by its re-encoded JSON plus
+// one byte for newline framing, which differs from the wire size when the
+// peer sends extra whitespace or fields the message type does not keep;
+// messages that fail to read or write are not counted.
+type Counting struct {
+	inner   Transport
+	wire    ByteCounter
+	read    atomic.Int64
+	written atomic.Int64
+}
+
+// NewCounting wraps inner with byte counters.
+func NewCounting(inner Transport) *Counting {
+	wire, _ := inner.(ByteCounter)
+	return &Counting{inner: inner, wire: wire}
+}
+
+// Read reads from the inner transport and counts the message's size.
+func (t *Counting) Read() (*jsonrpc.Message, error) {
+	msg, err := t.inner.Read()
+	if err != nil || t.wire != nil {
+		return msg, err
+	}
+	t.read.Add(messageSize(msg))
+	return msg, nil
+}
+
+// Write writes to the inner transport and counts the message's size.
+func (t *Counting) Write(msg *jsonrpc.Message) error {
+	if err := t.inner.Write(msg); err != nil || t.wire != nil {
+		return err
+	}
+	t.written.Add(messageSize(msg))
+	return nil
+}
+
+// Close closes the inner transport.
+func (t *Counting) Close() error {
+	return t.inner.Close()
+}
+
+// BytesRead returns the total size of messages read so far.
+func (t *Counting) BytesRead() int64 {
+	if t.wire != nil {
+		return t.wire.BytesRead()
+	}
+	return t.read.Load()
+}
+
+// BytesWritten returns the total size of messages written so far.
+func (t *Counting) BytesWritten() int64 {
+	if t.wire != nil {
+		return t.wire.BytesWritten()
+	}
+	return t.written.Load()
+}
+
+// messageSize estimates the wire size of msg as its JSON encoding plus a
+// newline.
+func messageSize(msg *jsonrpc.Message) int64 {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return 0
+	}
+	return int64(len(data)) + 1
+}
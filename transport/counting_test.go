@@ -0,0 +1,79 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/amarbel-llc/go-lib-mcp/jsonrpc"
+)
+
+func TestCountingMatchesWireBytes(t *testing.T) {
+	in := `{"jsonrpc": "2.0", "id": 1, "method": "ping", "extra": true}` + "\n" +
+		"\n" +
+		`not json` + "\n" +
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}` + "\n"
+	var out bytes.Buffer
+
+	stdio := NewStdio(strings.NewReader(in), &out)
+	tr := NewCounting(stdio)
+
+	for {
+		if _, err := tr.Read(); err != nil {
+			if _, ok := err.(*MessageError); ok {
+				continue
+			}
+			break
+		}
+	}
+	if got, want := tr.BytesRead(), int64(len(in)); got != want {
+		t.Errorf("BytesRead = %d, want wire size %d", got, want)
+	}
+
+	resp, _ := jsonrpc.NewResponse(jsonrpc.NewNumberID(1), map[string]any{})
+	if err := tr.Write(resp); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	stdio.WriteRaw([]byte("raw\n"))
+	if got, want := tr.BytesWritten(), int64(out.Len()); got != want {
+		t.Errorf("BytesWritten = %d, want wire size %d", got, want)
+	}
+}
+
+// messageOnly hides the ByteCounter of the transport it wraps.
+type messageOnly struct{ Transport }
+
+func TestCountingEstimatesWithoutWireCounts(t *testing.T) {
+	in := `{"jsonrpc":"2.0","id":1,"method":"ping"}` + "\n" +
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}` + "\n"
+	var out bytes.Buffer
+
+	tr := NewCounting(messageOnly{NewStdio(strings.NewReader(in), &out)})
+
+	var wantRead int64
+	for range 2 {
+		msg, err := tr.Read()
+		if err != nil {
+			t.Fatalf("Read: %v", err)
+		}
+		data, _ := json.Marshal(msg)
+		wantRead += int64(len(data)) + 1
+	}
+	if got := tr.BytesRead(); got != wantRead {
+		t.Errorf("BytesRead = %d, want %d", got, wantRead)
+	}
+
+	// The input is compact JSON, so the estimate matches the wire bytes.
+	if got, want := tr.BytesRead(), int64(len(in)); got != want {
+		t.Errorf("BytesRead = %d, want wire size %d", got, want)
+	}
+
+	resp, _ := jsonrpc.NewResponse(jsonrpc.NewNumberID(1), map[string]any{})
+	if err := tr.Write(resp); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if got, want := tr.BytesWritten(), int64(out.Len()); got != want {
+		t.Errorf("BytesWritten = %d, want %d", got, want)
+	}
+}
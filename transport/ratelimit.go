@@ -0,0 +1,94 @@
+package transport
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/amarbel-llc/go-lib-mcp/jsonrpc"
+)
+
+// RateLimited wraps a Transport and limits how fast messages are read from
+// it, using a token bucket that refills at rps tokens per second and holds up
+// to burst tokens. Read delivers a message only once a token is available, so
+// a flooding client is slowed down before any handler runs. Writes are not
+// limited.
+type RateLimited struct {
+	inner Transport
+	rps   float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewRateLimited wraps inner with a token bucket. A non-positive rps disables
+// limiting; burst is at least 1.
+func NewRateLimited(inner Transport, rps int, burst int) *RateLimited {
+	burst = max(burst, 1)
+	return &RateLimited{
+		inner:  inner,
+		rps:    float64(rps),
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+		done:   make(chan struct{}),
+	}
+}
+
+// Read reads the next message from the inner transport and waits for a
+// token before returning it. Errors, including io.EOF, are returned at once,
+// and Close interrupts a pending wait with io.EOF.
+func (t *RateLimited) Read() (*jsonrpc.Message, error) {
+	msg, err := t.inner.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	if wait := t.reserve(); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-t.done:
+			return nil, io.EOF
+		}
+	}
+
+	return msg, nil
+}
+
+// reserve takes a token, returning how long to wait until it is available.
+func (t *RateLimited) reserve() time.Duration {
+	if t.rps <= 0 {
+		return 0
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	t.tokens = min(t.burst, t.tokens+now.Sub(t.last).Seconds()*t.rps)
+	t.last = now
+
+	t.tokens--
+	if t.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-t.tokens / t.rps * float64(time.Second))
+}
+
+// Write writes to the inner transport.
+func (t *RateLimited) Write(msg *jsonrpc.Message) error {
+	return t.inner.Write(msg)
+}
+
+// Close interrupts any pending Read and closes the inner transport.
+func (t *RateLimited) Close() error {
+	t.closeOnce.Do(func() { close(t.done) })
+	return t.inner.Close()
+}
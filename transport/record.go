@@ -0,0 +1,157 @@
+package transport
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/amarbel-llc/go-lib-mcp/jsonrpc"
+)
+
+// Directions of recorded messages, from the point of view of the recorded
+// side (normally the server).
+const (
+	DirectionIn  = "in"
+	DirectionOut = "out"
+)
+
+// RecordEntry is one line of a session recording.
+type RecordEntry struct {
+	Direction string           `json:"dir"`
+	Time      time.Time        `json:"time"`
+	Message   *jsonrpc.Message `json:"message"`
+}
+
+// Recorder wraps a Transport and logs every message read and written through
+// it to a writer, one RecordEntry per line, in the order they passed
+// through. Recordings can be fed back to a server with NewReplayer.
+//
+// Failing to write the recording does not affect the session; Err reports
+// the first such failure.
+type Recorder struct {
+	inner Transport
+
+	mu  sync.Mutex
+	enc *json.Encoder
+	err error
+}
+
+// NewRecorder wraps inner, recording its messages to w.
+func NewRecorder(inner Transport, w io.Writer) *Recorder {
+	return &Recorder{inner: inner, enc: json.NewEncoder(w)}
+}
+
+// Read reads from the inner transport and records the message.
+func (t *Recorder) Read() (*jsonrpc.Message, error) {
+	msg, err := t.inner.Read()
+	if err != nil {
+		return msg, err
+	}
+	t.record(DirectionIn, msg)
+	return msg, nil
+}
+
+// Write writes to the inner transport and records the message.
+func (t *Recorder) Write(msg *jsonrpc.Message) error {
+	if err := t.inner.Write(msg); err != nil {
+		return err
+	}
+	t.record(DirectionOut, msg)
+	return nil
+}
+
+// Close closes the inner transport.
+func (t *Recorder) Close() error {
+	return t.inner.Close()
+}
+
+// Err returns the first error writing the recording, if any.
+func (t *Recorder) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
+func (t *Recorder) record(dir string, msg *jsonrpc.Message) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.err != nil {
+		return
+	}
+	if err := t.enc.Encode(RecordEntry{Direction: dir, Time: time.Now(), Message: msg}); err != nil {
+		t.err = fmt.Errorf("recording message: %w", err)
+	}
+}
+
+// Replayer is a Transport that replays a recording made by Recorder. Read
+// returns the recorded inbound messages in order, then io.EOF; recorded
+// outbound messages are skipped. Messages written to it are kept for
+// comparison with the recording rather than sent anywhere.
+type Replayer struct {
+	dec *json.Decoder
+
+	mu       sync.Mutex
+	expected []*jsonrpc.Message
+	written  []*jsonrpc.Message
+}
+
+// NewReplayer returns a Replayer reading a recording from r.
+func NewReplayer(r io.Reader) *Replayer {
+	return &Replayer{dec: json.NewDecoder(r)}
+}
+
+// Read returns the next recorded inbound message.
+func (t *Replayer) Read() (*jsonrpc.Message, error) {
+	for {
+		var entry RecordEntry
+		if err := t.dec.Decode(&entry); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, io.EOF
+			}
+			return nil, fmt.Errorf("reading recording: %w", err)
+		}
+		if entry.Message == nil {
+			continue
+		}
+
+		switch entry.Direction {
+		case DirectionIn:
+			return entry.Message, nil
+		case DirectionOut:
+			t.mu.Lock()
+			t.expected = append(t.expected, entry.Message)
+			t.mu.Unlock()
+		}
+	}
+}
+
+// Write keeps msg for Written.
+func (t *Replayer) Write(msg *jsonrpc.Message) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.written = append(t.written, msg)
+	return nil
+}
+
+// Close does nothing; the caller owns the recording's reader.
+func (t *Replayer) Close() error {
+	return nil
+}
+
+// Written returns the messages written during the replay, in order.
+func (t *Replayer) Written() []*jsonrpc.Message {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*jsonrpc.Message(nil), t.written...)
+}
+
+// Expected returns the outbound messages in the recording that Read has
+// passed over, in order; after Read returns io.EOF it is all of them.
+func (t *Replayer) Expected() []*jsonrpc.Message {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*jsonrpc.Message(nil), t.expected...)
+}
@@ -0,0 +1,70 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/amarbel-llc/go-lib-mcp/jsonrpc"
+)
+
+func TestRecorderReplayerRoundTrip(t *testing.T) {
+	in := `{"jsonrpc":"2.0","id":1,"method":"ping"}` + "\n" +
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}` + "\n"
+	var out, recording bytes.Buffer
+
+	rec := NewRecorder(NewStdio(strings.NewReader(in), &out), &recording)
+	if _, err := rec.Read(); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	resp, _ := jsonrpc.NewResponse(jsonrpc.NewNumberID(1), map[string]any{})
+	if err := rec.Write(resp); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if _, err := rec.Read(); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if err := rec.Err(); err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(recording.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("recording has %d lines, want 3:\n%s", len(lines), recording.String())
+	}
+	var dirs []string
+	for _, line := range lines {
+		var entry RecordEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("unmarshal %q: %v", line, err)
+		}
+		if entry.Time.IsZero() {
+			t.Errorf("entry %q has no timestamp", line)
+		}
+		dirs = append(dirs, entry.Direction)
+	}
+	if got := strings.Join(dirs, ","); got != "in,out,in" {
+		t.Errorf("directions = %s, want in,out,in", got)
+	}
+
+	rep := NewReplayer(&recording)
+	first, err := rep.Read()
+	if err != nil || first.Method != "ping" {
+		t.Fatalf("Read = %+v, %v; want ping", first, err)
+	}
+	second, err := rep.Read()
+	if err != nil || second.Method != "notifications/initialized" {
+		t.Fatalf("Read = %+v, %v; want notifications/initialized", second, err)
+	}
+	if _, err := rep.Read(); !errors.Is(err, io.EOF) {
+		t.Fatalf("Read at end = %v, want io.EOF", err)
+	}
+
+	rep.Write(resp)
+	if len(rep.Expected()) != 1 || len(rep.Written()) != 1 {
+		t.Fatalf("expected %d, written %d; want 1 each", len(rep.Expected()), len(rep.Written()))
+	}
+}
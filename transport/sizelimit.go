@@ -0,0 +1,75 @@
+package transport
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/amarbel-llc/go-lib-mcp/jsonrpc"
+)
+
+// ErrMessageTooLarge is returned by SizeLimited.Write when a message exceeds
+// the configured size, and wrapped in a MessageError by Stdio.Read when an
+// incoming line does.
+var ErrMessageTooLarge = errors.New("message too large")
+
+// SizeLimited wraps a Transport and refuses to write messages whose JSON
+// encoding exceeds a maximum size. It is a safety net for clients that drop
+// the connection on oversized messages; the output package's limits should
+// still be used to keep tool results small in the first place.
+//
+// Only writes are limited. The size counts the JSON encoding without framing,
+// so for Stdio the line on the wire is one byte longer. Stdio's own read side
+// accepts lines up to 1MB; a peer built on it will reject anything
+// larger, so maxBytes should stay below the peer's read limit.
+type SizeLimited struct {
+	inner    Transport
+	maxBytes int
+
+	// ReplaceWithError makes an oversized response get replaced by a JSON-RPC
+	// error response with the same ID, so the client is not left waiting.
+	// Write still returns ErrMessageTooLarge.
+	ReplaceWithError bool
+}
+
+// NewSizeLimited wraps inner so that messages larger than maxBytes are not written.
+func NewSizeLimited(inner Transport, maxBytes int) *SizeLimited {
+	return &SizeLimited{inner: inner, maxBytes: maxBytes}
+}
+
+// Read implements Transport.
+func (t *SizeLimited) Read() (*jsonrpc.Message, error) {
+	return t.inner.Read()
+}
+
+// Write implements Transport.
+func (t *SizeLimited) Write(msg *jsonrpc.Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshaling message: %w", err)
+	}
+
+	if len(data) <= t.maxBytes {
+		return t.inner.Write(msg)
+	}
+
+	tooLarge := fmt.Errorf("%w: %d bytes exceeds limit of %d", ErrMessageTooLarge, len(data), t.maxBytes)
+
+	if t.ReplaceWithError && msg.ID != nil && msg.Method == "" {
+		errResp, err := jsonrpc.NewErrorResponse(msg.RequestID(), jsonrpc.InternalError,
+			fmt.Sprintf("response too large: %d bytes exceeds limit of %d", len(data), t.maxBytes), nil)
+		if err != nil {
+			return err
+		}
+		if err := t.inner.Write(errResp); err != nil {
+			return err
+		}
+	}
+
+	return tooLarge
+}
+
+// Close implements Transport.
+func (t *SizeLimited) Close() error {
+	return t.inner.Close()
+}
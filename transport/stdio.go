@@ -2,10 +2,13 @@ package transport
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"sync"
+	"sync/atomic"
 
 	"github.com/amarbel-llc/go-lib-mcp/jsonrpc"
 )
@@ -14,41 +17,79 @@ import (
 // This differs from LSP which uses Content-Length headers.
 // Each JSON-RPC message is written on a single line, terminated by a newline.
 type Stdio struct {
-	scanner *bufio.Scanner
+	reader  *bufio.Reader
 	writer  io.Writer
-	closer  io.Closer
+	closers []io.Closer
 	mu      sync.Mutex
+
+	bytesRead    atomic.Int64
+	bytesWritten atomic.Int64
 }
 
+// maxLineSize is the largest incoming message Stdio accepts.
+const maxLineSize = 1024 * 1024
+
+// MessageError reports an incoming message that could not be used, such as
+// an oversized line or malformed JSON. The offending message has been
+// consumed, so the stream is still usable and the next Read continues with
+// the following message. Any other error from Read means the stream is broken.
+type MessageError struct {
+	// Err describes the problem.
+	Err error
+
+	// ID is the message's ID, if it could be recovered.
+	ID *jsonrpc.ID
+}
+
+func (e *MessageError) Error() string { return e.Err.Error() }
+func (e *MessageError) Unwrap() error { return e.Err }
+
 // NewStdio creates a new stdio transport.
 func NewStdio(r io.Reader, w io.Writer) *Stdio {
-	scanner := bufio.NewScanner(r)
-	// Increase buffer size for large messages (64KB initial, 1MB max)
-	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
 	return &Stdio{
-		scanner: scanner,
-		writer:  w,
+		reader: bufio.NewReaderSize(r, 64*1024),
+		writer: w,
 	}
 }
 
 // NewStdioWithCloser creates a new stdio transport with a closer.
 // The closer will be called when Close() is invoked.
 func NewStdioWithCloser(r io.Reader, w io.Writer, c io.Closer) *Stdio {
+	return NewStdioWithClosers(r, w, c)
+}
+
+// NewStdioWithClosers creates a new stdio transport that owns the given closers.
+// All of them are closed, in order, when Close() is invoked; nil closers are ignored.
+// This is useful when both ends are *os.File values that must be released.
+func NewStdioWithClosers(r io.Reader, w io.Writer, closers ...io.Closer) *Stdio {
 	t := NewStdio(r, w)
-	t.closer = c
+	for _, c := range closers {
+		if c != nil {
+			t.closers = append(t.closers, c)
+		}
+	}
 	return t
 }
 
 // Read reads a newline-delimited JSON message from the transport.
+// Lines over 1MB and lines that are not valid JSON-RPC are skipped and
+// reported as a *MessageError; reading can continue afterwards.
 func (t *Stdio) Read() (*jsonrpc.Message, error) {
-	if !t.scanner.Scan() {
-		if err := t.scanner.Err(); err != nil {
-			return nil, fmt.Errorf("reading message: %w", err)
+	line, tooLarge, err := t.readLine()
+	if err != nil {
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("reading message: %w", err)
+	}
+
+	if tooLarge {
+		return nil, &MessageError{
+			Err: fmt.Errorf("%w: line exceeds %d bytes", ErrMessageTooLarge, maxLineSize),
+			ID:  recoverID(line),
 		}
-		return nil, io.EOF
 	}
 
-	line := t.scanner.Bytes()
 	if len(line) == 0 {
 		// Skip empty lines and try again
 		return t.Read()
@@ -56,12 +97,78 @@ func (t *Stdio) Read() (*jsonrpc.Message, error) {
 
 	var msg jsonrpc.Message
 	if err := json.Unmarshal(line, &msg); err != nil {
-		return nil, fmt.Errorf("parsing message: %w", err)
+		return nil, &MessageError{
+			Err: fmt.Errorf("parsing message: %w", err),
+			ID:  recoverID(line),
+		}
 	}
 
 	return &msg, nil
 }
 
+// readLine reads the next line without its terminator. If the line is longer
+// than maxLineSize, the rest of it is discarded, the first maxLineSize bytes
+// are returned, and tooLarge is set. A final line without a newline is
+// returned as is; io.EOF is only returned when there is nothing left.
+func (t *Stdio) readLine() (line []byte, tooLarge bool, err error) {
+	for {
+		chunk, err := t.reader.ReadSlice('\n')
+		t.bytesRead.Add(int64(len(chunk)))
+
+		if !tooLarge {
+			content := bytes.TrimSuffix(chunk, []byte("\n"))
+			if len(line)+len(content) > maxLineSize {
+				tooLarge = true
+				chunk = content[:maxLineSize-len(line)]
+			}
+			line = append(line, chunk...)
+		}
+
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err != nil && (err != io.EOF || (len(line) == 0 && !tooLarge)) {
+			return nil, false, err
+		}
+		break
+	}
+
+	line = bytes.TrimSuffix(line, []byte("\n"))
+	line = bytes.TrimSuffix(line, []byte("\r"))
+	return line, tooLarge, nil
+}
+
+// recoverID looks for a top-level "id" in data, which may be truncated or
+// otherwise malformed past that point.
+func recoverID(data []byte) *jsonrpc.ID {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil
+	}
+
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil
+		}
+		if key != "id" {
+			continue
+		}
+
+		var id jsonrpc.ID
+		if err := json.Unmarshal(value, &id); err != nil || id.IsNull() {
+			return nil
+		}
+		return &id
+	}
+
+	return nil
+}
+
 // Write writes a newline-delimited JSON message to the transport.
 func (t *Stdio) Write(msg *jsonrpc.Message) error {
 	data, err := json.Marshal(msg)
@@ -72,17 +179,68 @@ func (t *Stdio) Write(msg *jsonrpc.Message) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
-	if _, err := fmt.Fprintf(t.writer, "%s\n", data); err != nil {
+	n, err := fmt.Fprintf(t.writer, "%s\n", data)
+	t.bytesWritten.Add(int64(n))
+	if err != nil {
 		return fmt.Errorf("writing message: %w", err)
 	}
 
 	return nil
 }
 
-// Close closes the transport.
+// WriteRaw writes p to the underlying writer while holding the same lock used
+// by Write, so it can never land inside a JSON-RPC message. Callers sharing
+// the transport's file descriptor for diagnostics should use WriteRaw (or
+// WithLock) instead of writing to the descriptor directly. Note that clients
+// expect every line on the MCP channel to be a JSON-RPC message.
+func (t *Stdio) WriteRaw(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	n, err := t.writer.Write(p)
+	t.bytesWritten.Add(int64(n))
+	return n, err
+}
+
+// WithLock calls fn with the underlying writer while holding the write lock,
+// for callers that need to emit several writes as one uninterrupted unit.
+// fn must not call Write or WriteRaw on the same transport.
+func (t *Stdio) WithLock(fn func(w io.Writer)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fn(countingWriter{t.writer, &t.bytesWritten})
+}
+
+// BytesRead returns the number of bytes read from the underlying reader,
+// including newlines and lines that were skipped or rejected.
+func (t *Stdio) BytesRead() int64 {
+	return t.bytesRead.Load()
+}
+
+// BytesWritten returns the number of bytes written to the underlying
+// writer, including newlines and raw writes.
+func (t *Stdio) BytesWritten() int64 {
+	return t.bytesWritten.Load()
+}
+
+// countingWriter adds the bytes written through it to n.
+type countingWriter struct {
+	w io.Writer
+	n *atomic.Int64
+}
+
+func (c countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+// Close closes the transport, closing every owned closer and joining their errors.
 func (t *Stdio) Close() error {
-	if t.closer != nil {
-		return t.closer.Close()
+	var errs []error
+	for _, c := range t.closers {
+		if err := c.Close(); err != nil {
+			errs = append(errs, err)
+		}
 	}
-	return nil
+	return errors.Join(errs...)
 }
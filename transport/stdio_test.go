@@ -0,0 +1,91 @@
+package transport
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestStdioSkipsOversizedLine(t *testing.T) {
+	big := `{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"data":"` +
+		strings.Repeat("x", maxLineSize) + `"}}`
+	input := big + "\n" + `{"jsonrpc":"2.0","id":8,"method":"ping"}` + "\n"
+
+	tr := NewStdio(strings.NewReader(input), io.Discard)
+
+	_, err := tr.Read()
+	var msgErr *MessageError
+	if !errors.As(err, &msgErr) {
+		t.Fatalf("Read error = %v, want *MessageError", err)
+	}
+	if !errors.Is(err, ErrMessageTooLarge) {
+		t.Errorf("error %v does not wrap ErrMessageTooLarge", err)
+	}
+	if msgErr.ID == nil || msgErr.ID.String() != "7" {
+		t.Errorf("recovered ID = %v, want 7", msgErr.ID)
+	}
+
+	msg, err := tr.Read()
+	if err != nil {
+		t.Fatalf("Read after oversized line: %v", err)
+	}
+	if msg.Method != "ping" {
+		t.Errorf("method = %q, want ping", msg.Method)
+	}
+
+	if _, err := tr.Read(); err != io.EOF {
+		t.Errorf("final Read error = %v, want io.EOF", err)
+	}
+}
+
+func TestStdioSkipsMalformedLine(t *testing.T) {
+	input := `{"jsonrpc":"2.0","id":"a","method":` + "\n" +
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`
+
+	tr := NewStdio(strings.NewReader(input), io.Discard)
+
+	_, err := tr.Read()
+	var msgErr *MessageError
+	if !errors.As(err, &msgErr) {
+		t.Fatalf("Read error = %v, want *MessageError", err)
+	}
+	if msgErr.ID == nil || msgErr.ID.String() != "a" {
+		t.Errorf("recovered ID = %v, want a", msgErr.ID)
+	}
+
+	msg, err := tr.Read()
+	if err != nil {
+		t.Fatalf("Read after malformed line: %v", err)
+	}
+	if msg.Method != "notifications/initialized" {
+		t.Errorf("method = %q", msg.Method)
+	}
+}
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error { return f() }
+
+func TestStdioWithClosers(t *testing.T) {
+	var order []string
+	closer := func(name string, err error) io.Closer {
+		return closerFunc(func() error {
+			order = append(order, name)
+			return err
+		})
+	}
+	errA := errors.New("a failed")
+	errC := errors.New("c failed")
+
+	tr := NewStdioWithClosers(strings.NewReader(""), io.Discard,
+		closer("a", errA), nil, closer("b", nil), nil, closer("c", errC))
+
+	err := tr.Close()
+	if got := strings.Join(order, ","); got != "a,b,c" {
+		t.Errorf("close order = %s, want a,b,c", got)
+	}
+	if !errors.Is(err, errA) || !errors.Is(err, errC) {
+		t.Errorf("Close err = %v, want both closer errors joined", err)
+	}
+}